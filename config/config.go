@@ -3,6 +3,7 @@ package config
 import (
 	"bytes"
 	"strings"
+	"unicode"
 
 	"github.com/fatih/structs"
 	"github.com/jeremywohl/flatten"
@@ -10,15 +11,39 @@ import (
 	"github.com/spf13/viper"
 )
 
+// Validator is implemented by config types with cross-field rules. ParseConfig calls
+// Validate on the decoded *T (after secret refs are resolved) and returns its error.
+type Validator interface {
+	Validate() error
+}
+
+// Option tweaks how ParseConfig / ParseConfigWithEmbedded build the config.
+type Option func(*options)
+
+type options struct {
+	resolveSecretRefs bool
+}
+
+// WithSecretRefs resolves file://, env:// and base64:// references in string fields
+// after unmarshalling (see resolveSecretRefs).
+func WithSecretRefs() Option {
+	return func(o *options) { o.resolveSecretRefs = true }
+}
+
 // ParseConfig behaves like before (no embedded defaults).
 // It just forwards to ParseConfigWithEmbedded with nil.
-func ParseConfig[T interface{}](configFilePaths []string) (*T, error) {
-	return ParseConfigWithEmbedded[T](configFilePaths, nil)
+func ParseConfig[T interface{}](configFilePaths []string, opts ...Option) (*T, error) {
+	return ParseConfigWithEmbedded[T](configFilePaths, nil, opts...)
 }
 
 // ParseConfigWithEmbedded tries to load config from disk,
 // and if the file is NOT found, falls back to embeddedYAML (if provided).
-func ParseConfigWithEmbedded[T interface{}](configFilePaths []string, embeddedYAML []byte) (*T, error) {
+func ParseConfigWithEmbedded[T interface{}](configFilePaths []string, embeddedYAML []byte, opts ...Option) (*T, error) {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
 	for _, v := range configFilePaths {
 		viper.AddConfigPath(v)
 	}
@@ -26,13 +51,10 @@ func ParseConfigWithEmbedded[T interface{}](configFilePaths []string, embeddedYA
 	viper.SetConfigName("config")
 	viper.SetConfigType("yaml")
 
-	if err := bindAllConfigKeys[T](); err != nil {
+	if err := bindEnv[T](viper.GetViper()); err != nil {
 		return nil, err
 	}
 
-	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
-	viper.AutomaticEnv()
-
 	err := viper.ReadInConfig()
 	if err != nil {
 		var nfErr viper.ConfigFileNotFoundError
@@ -46,17 +68,64 @@ func ParseConfigWithEmbedded[T interface{}](configFilePaths []string, embeddedYA
 		}
 	}
 
+	return decodeConfig[T](viper.GetViper(), o)
+}
+
+// ParseConfigWith binds env vars and decodes *T from v instead of the global viper. v is
+// used as is: nothing is read from disk, so it must already hold its config, e.g. loaded
+// with ReadRemoteConfig from Consul/etcd or populated with Set in tests.
+func ParseConfigWith[T interface{}](v *viper.Viper, opts ...Option) (*T, error) {
+	if v == nil {
+		return nil, errors.New("nil viper instance")
+	}
+
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if err := bindEnv[T](v); err != nil {
+		return nil, err
+	}
+	return decodeConfig[T](v, o)
+}
+
+// bindEnv makes every key of T overridable through env vars on v: a.b-c -> A_B_C, and
+// camelCase fields also as SCREAMING_SNAKE (Db.MaxPoolSize -> DB_MAX_POOL_SIZE).
+func bindEnv[T interface{}](v *viper.Viper) error {
+	if err := bindAllConfigKeys[T](v); err != nil {
+		return err
+	}
+	v.SetEnvKeyReplacer(envKeyReplacer)
+	v.AutomaticEnv()
+	return nil
+}
+
+// decodeConfig unmarshals v into *T, then resolves secret refs and validates.
+func decodeConfig[T interface{}](v *viper.Viper, o options) (*T, error) {
 	var c *T
-	if err := viper.Unmarshal(&c); err != nil {
+	if err := v.Unmarshal(&c, viper.DecodeHook(envDecodeHook())); err != nil {
 		return nil, errors.Wrap(err, "Unable to decode into struct")
 	}
 
+	if o.resolveSecretRefs && c != nil {
+		if err := resolveSecretRefs(c); err != nil {
+			return nil, err
+		}
+	}
+
+	if val, ok := interface{}(c).(Validator); ok && c != nil {
+		if err := val.Validate(); err != nil {
+			return nil, errors.Wrap(err, "Invalid config")
+		}
+	}
+
 	return c, nil
 }
 
 // Workaround for major viper issue with env variables, documented here
 // https://github.com/spf13/viper/issues/761
-func bindAllConfigKeys[T interface{}]() error {
+func bindAllConfigKeys[T interface{}](v *viper.Viper) error {
 	var cd T
 	// Transform config struct to map
 	confMap := structs.Map(cd)
@@ -67,11 +136,42 @@ func bindAllConfigKeys[T interface{}]() error {
 		return errors.Wrap(err, "Unable to flatten config")
 	}
 
-	// Bind each conf field to environment vars
+	// Bind each conf field to environment vars. The plain upper-cased name is listed
+	// first so it keeps priority when both forms are set. Viper skips SetEnvPrefix for
+	// explicitly named env vars, so the prefix is applied here.
+	prefix := ""
+	if p := v.GetEnvPrefix(); p != "" {
+		prefix = strings.ToUpper(p) + "_"
+	}
 	for key := range flat {
-		if err := viper.BindEnv(key); err != nil {
+		names := []string{prefix + strings.ToUpper(envKeyReplacer.Replace(key))}
+		if snake := prefix + screamingSnakeEnvName(key); snake != names[0] {
+			names = append(names, snake)
+		}
+		if err := v.BindEnv(append([]string{key}, names...)...); err != nil {
 			return errors.Wrapf(err, "Unable to bind env var: %s", key)
 		}
 	}
 	return nil
 }
+
+var envKeyReplacer = strings.NewReplacer(".", "_", "-", "_")
+
+// screamingSnakeEnvName converts each segment of a config key from camelCase to
+// SCREAMING_SNAKE: "Database.MaxPoolSize" -> "DATABASE_MAX_POOL_SIZE",
+// "TLSCertPath" -> "TLS_CERT_PATH".
+func screamingSnakeEnvName(key string) string {
+	var b strings.Builder
+	runes := []rune(envKeyReplacer.Replace(key))
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToUpper(r))
+	}
+	return b.String()
+}
@@ -0,0 +1,88 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+type testDatabase struct {
+	Host        string
+	MaxPoolSize int
+}
+
+type testConfig struct {
+	Database    testDatabase
+	TLSCertPath string
+}
+
+func TestScreamingSnakeEnvName(t *testing.T) {
+	tests := map[string]string{
+		"Database.MaxPoolSize": "DATABASE_MAX_POOL_SIZE",
+		"TLSCertPath":          "TLS_CERT_PATH",
+		"Http.Port2Bind":       "HTTP_PORT2_BIND",
+		"log-level":            "LOG_LEVEL",
+		"Database.Host":        "DATABASE_HOST",
+	}
+	for key, want := range tests {
+		if got := screamingSnakeEnvName(key); got != want {
+			t.Errorf("screamingSnakeEnvName(%q) = %q, want %q", key, got, want)
+		}
+	}
+}
+
+func TestParseConfigWithEnv(t *testing.T) {
+	t.Setenv("DATABASE_MAX_POOL_SIZE", "20")
+	t.Setenv("TLS_CERT_PATH", "/etc/tls/cert.pem")
+	t.Setenv("DATABASE_HOST", "db.internal")
+
+	c, err := ParseConfigWith[testConfig](viper.New())
+	if err != nil {
+		t.Fatalf("ParseConfigWith: %v", err)
+	}
+	if c.Database.MaxPoolSize != 20 {
+		t.Errorf("Database.MaxPoolSize = %d, want 20", c.Database.MaxPoolSize)
+	}
+	if c.TLSCertPath != "/etc/tls/cert.pem" {
+		t.Errorf("TLSCertPath = %q, want /etc/tls/cert.pem", c.TLSCertPath)
+	}
+	if c.Database.Host != "db.internal" {
+		t.Errorf("Database.Host = %q, want db.internal", c.Database.Host)
+	}
+}
+
+func TestParseConfigWithEnvPlainNameWins(t *testing.T) {
+	t.Setenv("DATABASE_MAXPOOLSIZE", "5")
+	t.Setenv("DATABASE_MAX_POOL_SIZE", "20")
+
+	c, err := ParseConfigWith[testConfig](viper.New())
+	if err != nil {
+		t.Fatalf("ParseConfigWith: %v", err)
+	}
+	if c.Database.MaxPoolSize != 5 {
+		t.Errorf("Database.MaxPoolSize = %d, want 5", c.Database.MaxPoolSize)
+	}
+}
+
+func TestParseConfigWithEnvPrefix(t *testing.T) {
+	t.Setenv("APP_DATABASE_MAX_POOL_SIZE", "20")
+	t.Setenv("APP_DATABASE_HOST", "db.internal")
+	// Unprefixed names must not leak in once a prefix is set.
+	t.Setenv("TLS_CERT_PATH", "/etc/tls/cert.pem")
+
+	v := viper.New()
+	v.SetEnvPrefix("app")
+	c, err := ParseConfigWith[testConfig](v)
+	if err != nil {
+		t.Fatalf("ParseConfigWith: %v", err)
+	}
+	if c.Database.MaxPoolSize != 20 {
+		t.Errorf("Database.MaxPoolSize = %d, want 20", c.Database.MaxPoolSize)
+	}
+	if c.Database.Host != "db.internal" {
+		t.Errorf("Database.Host = %q, want db.internal", c.Database.Host)
+	}
+	if c.TLSCertPath != "" {
+		t.Errorf("TLSCertPath = %q, want empty", c.TLSCertPath)
+	}
+}
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/go-viper/mapstructure/v2"
+	"github.com/pkg/errors"
+)
+
+// envDecodeHook keeps viper's default hooks and lets a single env var fill a slice
+// ("a, b, c") or a map ("k1=v1,k2=v2"). Values that come from the YAML file are
+// already slices/maps and pass through untouched.
+func envDecodeHook() mapstructure.DecodeHookFunc {
+	return mapstructure.ComposeDecodeHookFunc(
+		mapstructure.StringToTimeDurationHookFunc(),
+		stringToSliceHook,
+		stringToMapHook,
+	)
+}
+
+func stringToSliceHook(from reflect.Type, to reflect.Type, data interface{}) (interface{}, error) {
+	if from.Kind() != reflect.String || (to.Kind() != reflect.Slice && to.Kind() != reflect.Array) {
+		return data, nil
+	}
+	// []byte stays a plain string conversion.
+	if to.Elem().Kind() == reflect.Uint8 {
+		return data, nil
+	}
+
+	raw := strings.TrimSpace(data.(string))
+	if raw == "" {
+		return []string{}, nil
+	}
+	parts := strings.Split(raw, ",")
+	for i := range parts {
+		parts[i] = strings.TrimSpace(parts[i])
+	}
+	return parts, nil
+}
+
+func stringToMapHook(from reflect.Type, to reflect.Type, data interface{}) (interface{}, error) {
+	if from.Kind() != reflect.String || to.Kind() != reflect.Map {
+		return data, nil
+	}
+
+	out := make(map[string]interface{})
+	raw := strings.TrimSpace(data.(string))
+	if raw == "" {
+		return out, nil
+	}
+	for _, pair := range strings.Split(raw, ",") {
+		k, v, ok := strings.Cut(pair, "=")
+		k = strings.TrimSpace(k)
+		if !ok || k == "" {
+			return nil, errors.Errorf("Invalid map entry %q, want key=value", pair)
+		}
+		out[k] = strings.TrimSpace(v)
+	}
+	return out, nil
+}
@@ -0,0 +1,138 @@
+package config
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	fileRefPrefix   = "file://"
+	envRefPrefix    = "env://"
+	base64RefPrefix = "base64://"
+)
+
+// resolveSecretRefs walks the decoded config and replaces string values that reference a secret:
+//
+//	file:///run/secrets/db_pass  -> file content, trailing newline trimmed
+//	env://DB_PASSWORD            -> value of the env var (must be set)
+//	base64://c2VjcmV0            -> decoded bytes
+//
+// Nested structs, pointers, slices, arrays and string-valued maps are followed.
+func resolveSecretRefs(cfg interface{}) error {
+	return resolveValue(reflect.ValueOf(cfg), "")
+}
+
+func resolveValue(v reflect.Value, path string) error {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		if v.Kind() == reflect.Interface {
+			// Values behind an interface aren't addressable; resolve a copy and store it back.
+			elem := reflect.New(v.Elem().Type()).Elem()
+			elem.Set(v.Elem())
+			if err := resolveValue(elem, path); err != nil {
+				return err
+			}
+			if v.CanSet() {
+				v.Set(elem)
+			}
+			return nil
+		}
+		return resolveValue(v.Elem(), path)
+
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < v.NumField(); i++ {
+			if !t.Field(i).IsExported() {
+				continue
+			}
+			if err := resolveValue(v.Field(i), joinPath(path, t.Field(i).Name)); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := resolveValue(v.Index(i), joinPath(path, "["+strconv.Itoa(i)+"]")); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Map:
+		if v.Type().Elem().Kind() != reflect.String {
+			for _, key := range v.MapKeys() {
+				elem := reflect.New(v.Type().Elem()).Elem()
+				elem.Set(v.MapIndex(key))
+				if err := resolveValue(elem, joinPath(path, fmt.Sprint(key.Interface()))); err != nil {
+					return err
+				}
+				v.SetMapIndex(key, elem)
+			}
+			return nil
+		}
+		for _, key := range v.MapKeys() {
+			resolved, err := resolveSecretRef(v.MapIndex(key).String())
+			if err != nil {
+				return errors.Wrapf(err, "Unable to resolve secret for %s", joinPath(path, fmt.Sprint(key.Interface())))
+			}
+			v.SetMapIndex(key, reflect.ValueOf(resolved).Convert(v.Type().Elem()))
+		}
+
+	case reflect.String:
+		if !v.CanSet() {
+			return nil
+		}
+		resolved, err := resolveSecretRef(v.String())
+		if err != nil {
+			return errors.Wrapf(err, "Unable to resolve secret for %s", path)
+		}
+		v.SetString(resolved)
+	}
+	return nil
+}
+
+// resolveSecretRef returns s unchanged unless it starts with a known reference prefix.
+func resolveSecretRef(s string) (string, error) {
+	switch {
+	case strings.HasPrefix(s, fileRefPrefix):
+		b, err := os.ReadFile(strings.TrimPrefix(s, fileRefPrefix))
+		if err != nil {
+			return "", errors.Wrap(err, "failed to read secret file")
+		}
+		return strings.TrimRight(string(b), "\r\n"), nil
+
+	case strings.HasPrefix(s, envRefPrefix):
+		name := strings.TrimPrefix(s, envRefPrefix)
+		val, ok := os.LookupEnv(name)
+		if !ok {
+			return "", errors.Errorf("env var %s is not set", name)
+		}
+		return val, nil
+
+	case strings.HasPrefix(s, base64RefPrefix):
+		b, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(s, base64RefPrefix))
+		if err != nil {
+			return "", errors.Wrap(err, "failed to decode base64 secret")
+		}
+		return string(b), nil
+	}
+	return s, nil
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	if strings.HasPrefix(name, "[") {
+		return path + name
+	}
+	return path + "." + name
+}
@@ -0,0 +1,35 @@
+package cryptoctx
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"fmt"
+
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// AEAD selects the cipher that encrypts the PQ keypair under the DEK.
+type AEAD string
+
+const (
+	// AEADXChaCha20Poly1305 is the default, and what files without an "aead" field use.
+	AEADXChaCha20Poly1305 AEAD = "xchacha20-poly1305"
+	// AEADAESGCM is AES-256-GCM, for deployments that need FIPS-approved primitives.
+	AEADAESGCM AEAD = "aes-256-gcm"
+)
+
+// newAEAD returns the cipher for alg keyed with the 32-byte DEK; "" means the default.
+func newAEAD(alg AEAD, dek []byte) (cipher.AEAD, error) {
+	switch alg {
+	case "", AEADXChaCha20Poly1305:
+		return chacha20poly1305.NewX(dek)
+	case AEADAESGCM:
+		block, err := aes.NewCipher(dek)
+		if err != nil {
+			return nil, err
+		}
+		return cipher.NewGCM(block)
+	default:
+		return nil, fmt.Errorf("cryptoctx: unsupported AEAD %q", alg)
+	}
+}
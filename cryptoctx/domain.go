@@ -0,0 +1,106 @@
+package cryptoctx
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"github.com/cloudflare/circl/sign"
+	"github.com/cloudflare/circl/sign/schemes"
+)
+
+// ErrInvalidDomain is returned for an empty domain or one longer than 255 bytes.
+var ErrInvalidDomain = errors.New("cryptoctx: signing domain must be 1-255 bytes")
+
+const domainFramePrefix = "quantumauth:cryptoctx:domain:v1"
+
+// Domain-separated signing binds a signature to the purpose it was made for (e.g.
+// "login", "tx-approval"), so a signature from one protocol can't be replayed in another.
+// The verifier must supply the same domain.
+//
+// PQ schemes with context support (ML-DSA) take the domain as the FIPS 204 context
+// string. The TPM key, and PQ schemes without context support, sign a framed message
+// instead: domainFramePrefix || len(domain) || domain || msg.
+
+func checkDomain(domain string) error {
+	if len(domain) == 0 || len(domain) > 255 {
+		return ErrInvalidDomain
+	}
+	return nil
+}
+
+func domainFrame(domain string, msg []byte) []byte {
+	out := make([]byte, 0, len(domainFramePrefix)+1+len(domain)+len(msg))
+	out = append(out, domainFramePrefix...)
+	out = append(out, byte(len(domain)))
+	out = append(out, domain...)
+	return append(out, msg...)
+}
+
+// pqDomainInput returns the message and opts to hand to scheme.Sign / scheme.Verify.
+func pqDomainInput(scheme sign.Scheme, domain string, msg []byte) ([]byte, *sign.SignatureOpts) {
+	if scheme.SupportsContext() {
+		return msg, &sign.SignatureOpts{Context: domain}
+	}
+	return domainFrame(domain, msg), nil
+}
+
+// SignTPMDomainB64 is SignTPMB64 bound to domain; verify with VerifyTPMDomainB64.
+func (r *runtimeImpl) SignTPMDomainB64(ctx context.Context, domain string, msg []byte) (string, error) {
+	if err := checkDomain(domain); err != nil {
+		return "", err
+	}
+	return r.SignTPMB64(ctx, domainFrame(domain, msg))
+}
+
+// SignPQDomainB64 is SignPQB64 bound to domain; verify with VerifyPQDomainB64.
+func (r *runtimeImpl) SignPQDomainB64(ctx context.Context, domain string, msg []byte) (string, error) {
+	if err := checkDomain(domain); err != nil {
+		return "", err
+	}
+
+	kp, err := r.loadPQKeypair(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer kp.zeroize()
+
+	sk, err := r.scheme.UnmarshalBinaryPrivateKey(kp.Priv)
+	if err != nil {
+		return "", fmt.Errorf("cryptoctx: unmarshal PQ private key: %w", err)
+	}
+
+	input, opts := pqDomainInput(r.scheme, domain, msg)
+	sig := r.scheme.Sign(sk, input, opts)
+	if sig == nil {
+		return "", fmt.Errorf("cryptoctx: PQ sign failed")
+	}
+	r.auditSign(ctx, SignKeyPQ, msg)
+	return base64.RawStdEncoding.EncodeToString(sig), nil
+}
+
+// VerifyTPMDomainB64 verifies a SignTPMDomainB64 signature against a TPMPublicKeyB64 key.
+func VerifyTPMDomainB64(pubB64 string, domain string, msg []byte, sigB64 string) error {
+	if err := checkDomain(domain); err != nil {
+		return err
+	}
+	if err := verifyTPMSignatureB64(pubB64, domainFrame(domain, msg), sigB64); err != nil {
+		return fmt.Errorf("cryptoctx: %w", err)
+	}
+	return nil
+}
+
+// VerifyPQDomainB64 verifies a SignPQDomainB64 signature made with the named CIRCL scheme.
+func VerifyPQDomainB64(schemeName string, pubB64 string, domain string, msg []byte, sigB64 string) error {
+	if err := checkDomain(domain); err != nil {
+		return err
+	}
+	scheme := schemes.ByName(schemeName)
+	if scheme == nil {
+		return fmt.Errorf("cryptoctx: PQ scheme %q not found", schemeName)
+	}
+
+	input, opts := pqDomainInput(scheme, domain, msg)
+	return verifyPQSignatureB64(scheme, pubB64, input, sigB64, opts)
+}
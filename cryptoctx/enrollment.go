@@ -0,0 +1,141 @@
+package cryptoctx
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/cloudflare/circl/sign/schemes"
+)
+
+var ErrInvalidEnrollmentBundle = errors.New("cryptoctx: invalid enrollment bundle")
+
+// Bundle is the enrollment artifact: both public keys plus a TPM and a PQ signature
+// over the same canonical payload, which binds the two keys and the server nonce together.
+type Bundle struct {
+	V int `json:"v"`
+
+	TPMPublicKeyB64 string `json:"tpm_pub_b64"`
+	PQPublicKeyB64  string `json:"pq_pub_b64"`
+	PQScheme        string `json:"pq_scheme"`
+	NonceB64        string `json:"nonce_b64"`
+
+	TPMSignatureB64 string `json:"tpm_sig_b64"`
+	PQSignatureB64  string `json:"pq_sig_b64"`
+}
+
+// SigningPayload returns the canonical bytes both keys sign.
+func (b *Bundle) SigningPayload() []byte {
+	return []byte(strings.Join([]string{
+		"quantumauth:cryptoctx:enroll:v1",
+		fmt.Sprintf("TPM-PUB: %s", b.TPMPublicKeyB64),
+		fmt.Sprintf("PQ-SCHEME: %s", b.PQScheme),
+		fmt.Sprintf("PQ-PUB: %s", b.PQPublicKeyB64),
+		fmt.Sprintf("NONCE: %s", b.NonceB64),
+	}, "\n"))
+}
+
+func (r *runtimeImpl) EnrollmentBundle(ctx context.Context, nonce []byte) (*Bundle, error) {
+	if r == nil || r.tpm == nil {
+		return nil, fmt.Errorf("cryptoctx: TPM client not initialized")
+	}
+	if len(nonce) == 0 {
+		return nil, fmt.Errorf("cryptoctx: enrollment nonce is required")
+	}
+
+	pqPub, err := r.PQPublicKeyB64(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &Bundle{
+		V:               1,
+		TPMPublicKeyB64: r.tpmPubB64,
+		PQPublicKeyB64:  pqPub,
+		PQScheme:        r.scheme.Name(),
+		NonceB64:        base64.RawStdEncoding.EncodeToString(nonce),
+	}
+	payload := b.SigningPayload()
+
+	b.TPMSignatureB64, err = r.SignTPMB64(ctx, payload)
+	if err != nil {
+		return nil, fmt.Errorf("cryptoctx: TPM sign enrollment: %w", err)
+	}
+	b.PQSignatureB64, err = r.SignPQB64(ctx, payload)
+	if err != nil {
+		return nil, fmt.Errorf("cryptoctx: PQ sign enrollment: %w", err)
+	}
+	return b, nil
+}
+
+// VerifyEnrollmentBundle checks that bundle was produced for nonce and that
+// both signatures verify under the public keys it carries.
+func VerifyEnrollmentBundle(bundle *Bundle, nonce []byte) error {
+	if bundle == nil || bundle.V != 1 {
+		return ErrInvalidEnrollmentBundle
+	}
+	if len(nonce) == 0 || bundle.NonceB64 != base64.RawStdEncoding.EncodeToString(nonce) {
+		return fmt.Errorf("%w: nonce mismatch", ErrInvalidEnrollmentBundle)
+	}
+
+	payload := bundle.SigningPayload()
+
+	if err := verifyTPMSignatureB64(bundle.TPMPublicKeyB64, payload, bundle.TPMSignatureB64); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidEnrollmentBundle, err)
+	}
+
+	scheme := schemes.ByName(bundle.PQScheme)
+	if scheme == nil {
+		return fmt.Errorf("%w: unknown PQ scheme %q", ErrInvalidEnrollmentBundle, bundle.PQScheme)
+	}
+	pubBytes, err := base64.RawStdEncoding.DecodeString(bundle.PQPublicKeyB64)
+	if err != nil {
+		return fmt.Errorf("%w: PQ public key encoding", ErrInvalidEnrollmentBundle)
+	}
+	pk, err := scheme.UnmarshalBinaryPublicKey(pubBytes)
+	if err != nil {
+		return fmt.Errorf("%w: PQ public key: %v", ErrInvalidEnrollmentBundle, err)
+	}
+	sig, err := base64.RawStdEncoding.DecodeString(bundle.PQSignatureB64)
+	if err != nil {
+		return fmt.Errorf("%w: PQ signature encoding", ErrInvalidEnrollmentBundle)
+	}
+	if !scheme.Verify(pk, payload, sig, nil) {
+		return fmt.Errorf("%w: PQ signature does not verify", ErrInvalidEnrollmentBundle)
+	}
+	return nil
+}
+
+// verifyTPMSignatureB64 verifies a tpmdevice signature: base64(R||S) over SHA-256(msg)
+// with a base64(0x04||X||Y) P-256 public key.
+func verifyTPMSignatureB64(pubB64 string, msg []byte, sigB64 string) error {
+	pub, err := base64.RawStdEncoding.DecodeString(pubB64)
+	if err != nil || len(pub) != 65 || pub[0] != 0x04 {
+		return errors.New("malformed TPM public key")
+	}
+	sig, err := base64.RawStdEncoding.DecodeString(sigB64)
+	if err != nil || len(sig) != 64 {
+		return errors.New("malformed TPM signature")
+	}
+
+	curve := elliptic.P256()
+	x := new(big.Int).SetBytes(pub[1:33])
+	y := new(big.Int).SetBytes(pub[33:])
+	if !curve.IsOnCurve(x, y) {
+		return errors.New("TPM public key not on P-256")
+	}
+
+	d := sha256.Sum256(msg)
+	rr := new(big.Int).SetBytes(sig[:32])
+	ss := new(big.Int).SetBytes(sig[32:])
+	if !ecdsa.Verify(&ecdsa.PublicKey{Curve: curve, X: x, Y: y}, d[:], rr, ss) {
+		return errors.New("TPM signature does not verify")
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package cryptoctx
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// EnvelopeInfo is the plaintext metadata of a PQ key file.
+type EnvelopeInfo struct {
+	Version   int
+	Label     string
+	SigScheme string    // empty for files written before the field existed
+	KEMScheme string    // set for v2 (hybrid KEM) envelopes
+	AEAD      AEAD      // cipher of the payload
+	CreatedAt time.Time // zero for files written before the field existed
+
+	// Key generation: 1 for the first key, +1 per RotatePQKeypair; 0 for files
+	// written before the field existed
+	Generation uint64
+}
+
+// InspectEnvelope reads the PQ key file at path and returns its metadata without
+// unsealing or decrypting anything, so it needs no TPM. The fields are not
+// authenticated; use them for diagnostics and inventory, not for trust decisions.
+func InspectEnvelope(path string) (*EnvelopeInfo, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrMissingPQKeyFile
+		}
+		return nil, fmt.Errorf("cryptoctx: read PQ key file: %w", err)
+	}
+
+	var env pqEnvelopeV1
+	if err := json.Unmarshal(b, &env); err != nil {
+		return nil, fmt.Errorf("cryptoctx: unmarshal envelope: %w", err)
+	}
+	if env.V != 1 && env.V != 2 {
+		return nil, fmt.Errorf("cryptoctx: unsupported pq envelope version: %d", env.V)
+	}
+
+	info := &EnvelopeInfo{
+		Version:    env.V,
+		Label:      env.Label,
+		SigScheme:  env.SigScheme,
+		KEMScheme:  env.KEMScheme,
+		AEAD:       env.AEAD,
+		Generation: env.Generation,
+	}
+	if info.AEAD == "" {
+		info.AEAD = AEADXChaCha20Poly1305
+	}
+	if env.CreatedAt != "" {
+		t, err := time.Parse(time.RFC3339, env.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("cryptoctx: envelope created_at: %w", err)
+		}
+		info.CreatedAt = t
+	}
+	return info, nil
+}
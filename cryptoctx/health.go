@@ -0,0 +1,49 @@
+package cryptoctx
+
+import (
+	"context"
+	"fmt"
+)
+
+var healthCheckMessage = []byte("quantumauth:cryptoctx:healthcheck:v1")
+
+// HealthCheck runs the full sign/verify loop for both keys: the PQ key file must
+// unseal, and a TPM and a PQ signature over a fixed message must verify against
+// the corresponding public keys. The error names the component that failed.
+func (r *runtimeImpl) HealthCheck(ctx context.Context) error {
+	if r == nil || r.tpm == nil {
+		return fmt.Errorf("cryptoctx: health check: TPM client not initialized")
+	}
+
+	tpmSig, err := r.SignTPMB64(ctx, healthCheckMessage)
+	if err != nil {
+		return fmt.Errorf("cryptoctx: health check: TPM sign: %w", err)
+	}
+	if err := verifyTPMSignatureB64(r.tpmPubB64, healthCheckMessage, tpmSig); err != nil {
+		return fmt.Errorf("cryptoctx: health check: TPM verify: %w", err)
+	}
+
+	kp, err := r.loadPQKeypair(ctx)
+	if err != nil {
+		return fmt.Errorf("cryptoctx: health check: PQ key file: %w", err)
+	}
+	defer kp.zeroize()
+
+	sk, err := r.scheme.UnmarshalBinaryPrivateKey(kp.Priv)
+	if err != nil {
+		return fmt.Errorf("cryptoctx: health check: PQ private key (%s): %w", r.scheme.Name(), err)
+	}
+	pk, err := r.scheme.UnmarshalBinaryPublicKey(kp.Pub)
+	if err != nil {
+		return fmt.Errorf("cryptoctx: health check: PQ public key (%s): %w", r.scheme.Name(), err)
+	}
+
+	sig := r.scheme.Sign(sk, healthCheckMessage, nil)
+	if sig == nil {
+		return fmt.Errorf("cryptoctx: health check: PQ sign (%s) failed", r.scheme.Name())
+	}
+	if !r.scheme.Verify(pk, healthCheckMessage, sig, nil) {
+		return fmt.Errorf("cryptoctx: health check: PQ verify (%s): signature does not verify", r.scheme.Name())
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package cryptoctx
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+)
+
+// SignHybridB64 signs msg with both the TPM key and the PQ key, for verifiers that
+// require both during the classical-to-PQ transition. Both signatures cover the same
+// copy of msg, and the PQ key file's read lock is held across both, so a concurrent
+// RotatePQKeypair can't leave the pair signed under different key generations.
+// Check the pair with Verifier.VerifyHybrid or VerifyHybridB64.
+func (r *runtimeImpl) SignHybridB64(ctx context.Context, msg []byte) (tpmSigB64, pqSigB64 string, err error) {
+	if r == nil || r.tpm == nil {
+		return "", "", fmt.Errorf("cryptoctx: TPM client not initialized")
+	}
+	msg = append([]byte(nil), msg...)
+
+	r.keyMu.RLock()
+	defer r.keyMu.RUnlock()
+
+	kp, err := r.loadPQKeypairLocked(ctx)
+	if err != nil {
+		return "", "", err
+	}
+	defer kp.zeroize()
+
+	sk, err := r.scheme.UnmarshalBinaryPrivateKey(kp.Priv)
+	if err != nil {
+		return "", "", fmt.Errorf("cryptoctx: unmarshal PQ private key: %w", err)
+	}
+
+	tpmSigB64, err = r.signTPM(ctx, msg)
+	if err != nil {
+		return "", "", err
+	}
+	pqSig := r.scheme.Sign(sk, msg, nil)
+	if pqSig == nil {
+		return "", "", fmt.Errorf("cryptoctx: PQ sign failed")
+	}
+
+	r.auditSign(ctx, SignKeyTPM, msg)
+	r.auditSign(ctx, SignKeyPQ, msg)
+	return tpmSigB64, base64.RawStdEncoding.EncodeToString(pqSig), nil
+}
+
+// VerifyHybridB64 verifies a SignHybridB64 pair made with the named CIRCL scheme. Both
+// signatures must verify; the error names the one that didn't.
+func VerifyHybridB64(schemeName, tpmPubB64, pqPubB64 string, msg []byte, tpmSigB64, pqSigB64 string) error {
+	v, err := NewVerifier(VerifierConfig{PQSchemeName: schemeName})
+	if err != nil {
+		return err
+	}
+	return v.VerifyHybrid(tpmPubB64, pqPubB64, msg, tpmSigB64, pqSigB64)
+}
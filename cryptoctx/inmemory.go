@@ -0,0 +1,141 @@
+package cryptoctx
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"github.com/google/go-tpm/tpmutil"
+
+	"github.com/quantumauth-io/quantum-go-utils/tpmdevice"
+)
+
+// NewInMemory returns a Runtime for tests and CI that needs no TPM. The TPM key is an
+// in-process P-256 key and the DEK is sealed with AES-256-GCM under an in-process key,
+// so both vanish with the runtime: a key file it writes can only be read back by the
+// same runtime. Everything else, including the envelope format, PQLabel, PQKeyFilePath
+// and the ML-DSA sign/verify path, is the production code. cfg.TPM, cfg.OwnerAuth and
+// cfg.SealingParent are ignored. Not for production use.
+func NewInMemory(ctx context.Context, cfg Config) (Runtime, error) {
+	rt, err := newRuntime(ctx, cfg, func() (tpmdevice.Client, tpmdevice.Sealer, error) {
+		signer, err := newMemSigner()
+		if err != nil {
+			return nil, nil, err
+		}
+		sealer, err := newMemSealer()
+		if err != nil {
+			return nil, nil, err
+		}
+		return signer, sealer, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rt, nil
+}
+
+// memSigner is a tpmdevice.Client backed by an in-process ECDSA key, producing the same
+// signature and public key encodings as the TPM client.
+type memSigner struct {
+	key    *ecdsa.PrivateKey
+	pub    []byte
+	pubB64 string
+}
+
+var _ tpmdevice.Client = (*memSigner)(nil)
+
+func newMemSigner() (*memSigner, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("cryptoctx: in-memory signer key: %w", err)
+	}
+	pub := make([]byte, 65)
+	pub[0] = 0x04
+	key.X.FillBytes(pub[1:33])
+	key.Y.FillBytes(pub[33:])
+	return &memSigner{key: key, pub: pub, pubB64: base64.RawStdEncoding.EncodeToString(pub)}, nil
+}
+
+func (m *memSigner) Handle() tpmutil.Handle { return 0 }
+func (m *memSigner) PublicKey() []byte      { return append([]byte(nil), m.pub...) }
+func (m *memSigner) PublicKeyB64() string   { return m.pubB64 }
+func (m *memSigner) RequiresAuth() bool     { return false }
+func (m *memSigner) Close() error           { return nil }
+
+func (m *memSigner) Sign(msg []byte) ([]byte, error) {
+	d := sha256.Sum256(msg)
+	r, s, err := ecdsa.Sign(rand.Reader, m.key, d[:])
+	if err != nil {
+		return nil, fmt.Errorf("cryptoctx: in-memory sign: %w", err)
+	}
+	out := make([]byte, 64)
+	r.FillBytes(out[:32])
+	s.FillBytes(out[32:])
+	return out, nil
+}
+
+func (m *memSigner) SignB64(msg []byte) (string, error) {
+	raw, err := m.Sign(msg)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawStdEncoding.EncodeToString(raw), nil
+}
+
+func (m *memSigner) SignWithAuth(msg []byte, auth string) ([]byte, error) {
+	return m.Sign(msg)
+}
+
+func (m *memSigner) SignWithAuthB64(msg []byte, auth string) (string, error) {
+	return m.SignB64(msg)
+}
+
+func (m *memSigner) CreationAttestation() ([]byte, error) {
+	return nil, errors.New("cryptoctx: in-memory signer has no creation attestation")
+}
+
+// memSealer seals with AES-256-GCM under a key that lives only in this process, with
+// the label as additional data so blobs don't unseal under another label.
+type memSealer struct {
+	aead cipher.AEAD
+}
+
+func newMemSealer() (*memSealer, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("cryptoctx: in-memory sealer key: %w", err)
+	}
+	defer zeroBytes(key)
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &memSealer{aead: aead}, nil
+}
+
+func (m *memSealer) Seal(ctx context.Context, label string, secret []byte) ([]byte, error) {
+	nonce := make([]byte, m.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("cryptoctx: in-memory seal nonce: %w", err)
+	}
+	return m.aead.Seal(nonce, nonce, secret, []byte(label)), nil
+}
+
+func (m *memSealer) Unseal(ctx context.Context, label string, blob []byte) ([]byte, error) {
+	if len(blob) < m.aead.NonceSize() {
+		return nil, errors.New("cryptoctx: in-memory sealed blob too short")
+	}
+	n := m.aead.NonceSize()
+	return m.aead.Open(nil, blob[:n], blob[n:], []byte(label))
+}
@@ -0,0 +1,248 @@
+package cryptoctx
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/cloudflare/circl/kem"
+	"golang.org/x/crypto/hkdf"
+)
+
+// When a KEM is configured the DEK is no longer random-then-sealed. Instead:
+//
+//	dek = HKDF-SHA256(tpmShare || kemSharedSecret, info = label)
+//
+// tpmShare is sealed by the TPM and kemSharedSecret is encapsulated to a KEM key
+// kept in a separate file, so unwrapping needs both the TPM and the KEM private key.
+// The KEM private key is itself encrypted under a random KEK sealed by the TPM (the KEM
+// key is too large to seal directly), so a copy of the key files alone opens nothing.
+
+// errKEMKeyUnsealable means the KEM key file was sealed by another TPM (or tampered with).
+var errKEMKeyUnsealable = fmt.Errorf("%w: KEM key file does not unseal", ErrCorruptOrTampered)
+
+type kemKeyFile struct {
+	V      int    `json:"v"`
+	Scheme string `json:"scheme"`
+
+	// v1 only: the private key in plaintext. Read to migrate, never written.
+	Priv []byte `json:"priv,omitempty"`
+
+	// v2: the private key encrypted under a KEK sealed to this TPM
+	SealedKEKB64 string `json:"sealed_kek_b64,omitempty"`
+	AEAD         AEAD   `json:"aead,omitempty"`
+	NonceB64     string `json:"nonce_b64,omitempty"`
+	CTB64        string `json:"ct_b64,omitempty"`
+}
+
+func defaultKEMKeyPath(pqPath string) string {
+	return pqPath + ".kem"
+}
+
+// loadOrCreateKEMKey returns the KEM private key, generating it on first use. A v1
+// file, which held the key in plaintext, is rewritten sealed.
+func (r *runtimeImpl) loadOrCreateKEMKey(ctx context.Context) (kem.PrivateKey, error) {
+	b, err := os.ReadFile(r.kemKeyPath)
+	if os.IsNotExist(err) {
+		return r.createKEMKey(ctx)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cryptoctx: read KEM key file: %w", err)
+	}
+
+	var kf kemKeyFile
+	if err := json.Unmarshal(b, &kf); err != nil {
+		return nil, fmt.Errorf("cryptoctx: unmarshal KEM key file: %w", err)
+	}
+	if kf.Scheme != r.kem.Name() {
+		return nil, ErrCorruptOrTampered
+	}
+
+	var priv []byte
+	switch kf.V {
+	case 1:
+		priv = kf.Priv
+	case 2:
+		priv, err = r.openKEMKey(ctx, &kf)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, ErrCorruptOrTampered
+	}
+	defer zeroBytes(priv)
+
+	sk, err := r.kem.UnmarshalBinaryPrivateKey(priv)
+	if err != nil {
+		return nil, ErrCorruptOrTampered
+	}
+	if kf.V == 1 {
+		if err := r.writeKEMKey(ctx, priv); err != nil {
+			return nil, fmt.Errorf("cryptoctx: migrate plaintext KEM key: %w", err)
+		}
+	}
+	return sk, nil
+}
+
+// createKEMKey generates a KEM key and replaces the key file with it.
+func (r *runtimeImpl) createKEMKey(ctx context.Context) (kem.PrivateKey, error) {
+	_, sk, err := r.kem.GenerateKeyPair()
+	if err != nil {
+		return nil, fmt.Errorf("cryptoctx: KEM keygen failed: %w", err)
+	}
+	priv, err := sk.MarshalBinary()
+	if err != nil {
+		return nil, fmt.Errorf("cryptoctx: marshal KEM priv: %w", err)
+	}
+	defer zeroBytes(priv)
+
+	if err := r.writeKEMKey(ctx, priv); err != nil {
+		return nil, err
+	}
+	return sk, nil
+}
+
+// writeKEMKey encrypts priv under a fresh KEK sealed to the TPM and replaces the key file.
+func (r *runtimeImpl) writeKEMKey(ctx context.Context, priv []byte) error {
+	kek := make([]byte, 32)
+	if _, err := rand.Read(kek); err != nil {
+		return fmt.Errorf("cryptoctx: rand KEM kek: %w", err)
+	}
+	defer zeroBytes(kek)
+
+	sealed, err := r.sealer.Seal(ctx, r.pqLabel, kek)
+	if err != nil {
+		return fmt.Errorf("cryptoctx: seal KEM kek: %w", err)
+	}
+
+	aead, err := newAEAD(r.aead, kek)
+	if err != nil {
+		return fmt.Errorf("cryptoctx: aead: %w", err)
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("cryptoctx: rand nonce: %w", err)
+	}
+	ct := aead.Seal(nil, nonce, priv, r.kemAAD())
+
+	out, err := json.MarshalIndent(kemKeyFile{
+		V:            2,
+		Scheme:       r.kem.Name(),
+		SealedKEKB64: base64.StdEncoding.EncodeToString(sealed),
+		AEAD:         r.aead,
+		NonceB64:     base64.StdEncoding.EncodeToString(nonce),
+		CTB64:        base64.StdEncoding.EncodeToString(ct),
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("cryptoctx: marshal KEM key file: %w", err)
+	}
+	return atomicWriteFile(r.kemKeyPath, out, 0o600)
+}
+
+// openKEMKey unseals the KEK of a v2 key file and decrypts the private key with it.
+func (r *runtimeImpl) openKEMKey(ctx context.Context, kf *kemKeyFile) ([]byte, error) {
+	sealed, err := base64.StdEncoding.DecodeString(kf.SealedKEKB64)
+	if err != nil {
+		return nil, ErrCorruptOrTampered
+	}
+	nonce, err := base64.StdEncoding.DecodeString(kf.NonceB64)
+	if err != nil {
+		return nil, ErrCorruptOrTampered
+	}
+	ct, err := base64.StdEncoding.DecodeString(kf.CTB64)
+	if err != nil {
+		return nil, ErrCorruptOrTampered
+	}
+
+	kek, err := r.sealer.Unseal(ctx, r.pqLabel, sealed)
+	if err != nil || len(kek) != 32 {
+		if kek != nil {
+			zeroBytes(kek)
+		}
+		return nil, errKEMKeyUnsealable
+	}
+	defer zeroBytes(kek)
+
+	aead, err := newAEAD(kf.AEAD, kek)
+	if err != nil {
+		return nil, fmt.Errorf("cryptoctx: aead: %w", err)
+	}
+	if len(nonce) != aead.NonceSize() {
+		return nil, ErrCorruptOrTampered
+	}
+	priv, err := aead.Open(nil, nonce, ct, r.kemAAD())
+	if err != nil {
+		return nil, ErrCorruptOrTampered
+	}
+	return priv, nil
+}
+
+// kemAAD binds the KEM key file to its label, scheme and path, like aad does the PQ file.
+func (r *runtimeImpl) kemAAD() []byte {
+	abs := r.kemKeyPath
+	if a, err := filepath.Abs(r.kemKeyPath); err == nil {
+		abs = a
+	}
+	return []byte("quantumauth:cryptoctx:kem:v2|" + r.pqLabel + "|" + r.kem.Name() + "|" + abs)
+}
+
+// encapsulateDEK derives the DEK from tpmShare and a fresh KEM encapsulation.
+func (r *runtimeImpl) encapsulateDEK(ctx context.Context, tpmShare []byte) (dek []byte, kemCT []byte, err error) {
+	sk, err := r.loadOrCreateKEMKey(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ct, ss, err := r.kem.Encapsulate(sk.Public())
+	if err != nil {
+		return nil, nil, fmt.Errorf("cryptoctx: KEM encapsulate: %w", err)
+	}
+	defer zeroBytes(ss)
+
+	dek, err = r.deriveHybridDEK(tpmShare, ss)
+	if err != nil {
+		return nil, nil, err
+	}
+	return dek, ct, nil
+}
+
+// decapsulateDEK recovers the DEK from tpmShare and the envelope's KEM ciphertext.
+func (r *runtimeImpl) decapsulateDEK(ctx context.Context, tpmShare []byte, kemCT []byte) ([]byte, error) {
+	sk, err := r.loadOrCreateKEMKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ss, err := r.kem.Decapsulate(sk, kemCT)
+	if err != nil {
+		return nil, ErrCorruptOrTampered
+	}
+	defer zeroBytes(ss)
+
+	return r.deriveHybridDEK(tpmShare, ss)
+}
+
+func (r *runtimeImpl) deriveHybridDEK(tpmShare []byte, kemSS []byte) ([]byte, error) {
+	if len(tpmShare) == 0 || len(kemSS) == 0 {
+		return nil, errors.New("cryptoctx: empty DEK share")
+	}
+
+	secret := make([]byte, 0, len(tpmShare)+len(kemSS))
+	secret = append(secret, tpmShare...)
+	secret = append(secret, kemSS...)
+	defer zeroBytes(secret)
+
+	info := []byte("quantumauth:cryptoctx:dek:v2|" + r.pqLabel + "|" + r.kem.Name())
+	dek := make([]byte, 32)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, secret, nil, info), dek); err != nil {
+		return nil, fmt.Errorf("cryptoctx: derive DEK: %w", err)
+	}
+	return dek, nil
+}
@@ -0,0 +1,112 @@
+package cryptoctx
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func newKEMRuntime(t *testing.T) *runtimeImpl {
+	t.Helper()
+	rt, err := NewInMemory(context.Background(), Config{
+		PQKeyFilePath:   filepath.Join(t.TempDir(), "pqkeys.json.enc"),
+		PQLabel:         "test",
+		PQKEMSchemeName: "ML-KEM-768",
+	})
+	if err != nil {
+		t.Fatalf("NewInMemory: %v", err)
+	}
+	return rt.(*runtimeImpl)
+}
+
+func readKEMKeyFile(t *testing.T, path string) kemKeyFile {
+	t.Helper()
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read KEM key file: %v", err)
+	}
+	var kf kemKeyFile
+	if err := json.Unmarshal(b, &kf); err != nil {
+		t.Fatalf("unmarshal KEM key file: %v", err)
+	}
+	return kf
+}
+
+func TestKEMKeyFileIsSealed(t *testing.T) {
+	ctx := context.Background()
+	r := newKEMRuntime(t)
+	if err := r.EnsurePQKeypair(ctx); err != nil {
+		t.Fatalf("EnsurePQKeypair: %v", err)
+	}
+
+	kf := readKEMKeyFile(t, r.kemKeyPath)
+	if kf.V != 2 || len(kf.Priv) != 0 || kf.SealedKEKB64 == "" {
+		t.Fatalf("KEM key file v=%d priv=%d bytes sealed=%t, want sealed v2", kf.V, len(kf.Priv), kf.SealedKEKB64 != "")
+	}
+	if _, err := r.SignPQB64(ctx, []byte("msg")); err != nil {
+		t.Fatalf("SignPQB64: %v", err)
+	}
+}
+
+func TestKEMKeyFileBoundToPath(t *testing.T) {
+	ctx := context.Background()
+	r := newKEMRuntime(t)
+	if err := r.EnsurePQKeypair(ctx); err != nil {
+		t.Fatalf("EnsurePQKeypair: %v", err)
+	}
+
+	moved := r.kemKeyPath + ".moved"
+	if err := os.Rename(r.kemKeyPath, moved); err != nil {
+		t.Fatal(err)
+	}
+	r.kemKeyPath = moved
+	if _, err := r.loadOrCreateKEMKey(ctx); err == nil {
+		t.Fatal("moved KEM key file opened, want ErrCorruptOrTampered")
+	}
+}
+
+func TestKEMKeyFileMigratesPlaintext(t *testing.T) {
+	ctx := context.Background()
+	r := newKEMRuntime(t)
+
+	_, sk, err := r.kem.GenerateKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	priv, err := sk.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	v1, err := json.Marshal(kemKeyFile{V: 1, Scheme: r.kem.Name(), Priv: priv})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(r.kemKeyPath, v1, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := r.loadOrCreateKEMKey(ctx)
+	if err != nil {
+		t.Fatalf("loadOrCreateKEMKey: %v", err)
+	}
+	if kf := readKEMKeyFile(t, r.kemKeyPath); kf.V != 2 || len(kf.Priv) != 0 {
+		t.Fatalf("KEM key file not rewritten sealed: v=%d priv=%d bytes", kf.V, len(kf.Priv))
+	}
+
+	again, err := r.loadOrCreateKEMKey(ctx)
+	if err != nil {
+		t.Fatalf("reload migrated KEM key: %v", err)
+	}
+	for _, k := range []interface{ MarshalBinary() ([]byte, error) }{got, again} {
+		b, err := k.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(b, priv) {
+			t.Fatal("migrated KEM key differs from the plaintext one")
+		}
+	}
+}
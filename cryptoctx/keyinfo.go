@@ -0,0 +1,38 @@
+package cryptoctx
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// KeyInfo describes the runtime's PQ key without its key material.
+type KeyInfo struct {
+	Path       string
+	SchemeName string    // empty for files written before the field existed
+	CreatedAt  time.Time // from Config.Now when the key was generated; zero for old files
+	Generation uint64    // 1 for the first key, +1 per RotatePQKeypair; 0 for old files
+}
+
+// KeyInfo reads the PQ key file's plaintext metadata. It neither unseals the DEK nor
+// decrypts the key, so it is cheap enough for key-age alerting.
+func (r *runtimeImpl) KeyInfo(ctx context.Context) (KeyInfo, error) {
+	_ = ctx
+	if r == nil {
+		return KeyInfo{}, fmt.Errorf("cryptoctx: runtime is nil")
+	}
+
+	r.keyMu.RLock()
+	defer r.keyMu.RUnlock()
+
+	info, err := InspectEnvelope(r.pqPath)
+	if err != nil {
+		return KeyInfo{}, err
+	}
+	return KeyInfo{
+		Path:       r.pqPath,
+		SchemeName: info.SigScheme,
+		CreatedAt:  info.CreatedAt,
+		Generation: info.Generation,
+	}, nil
+}
@@ -0,0 +1,113 @@
+package cryptoctx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/cloudflare/circl/sign"
+)
+
+// ErrInvalidLabel is returned for a keyring label that is empty, longer than 64 bytes or
+// contains anything but letters, digits, '.', '_' and '-'.
+var ErrInvalidLabel = errors.New("cryptoctx: PQ label must be 1-64 characters of [A-Za-z0-9._-]")
+
+// Keyring: besides its own key (Config.PQLabel), a runtime can hold one PQ key per
+// extra label, e.g. per tenant. Each label gets its own envelope file,
+// <PQKeyFilePath>.label-<label>.enc, created on first use; naming it after the key file
+// keeps runtimes with different key files in one directory apart. The DEK is sealed
+// under the label and the AAD binds label and path, so files can't be swapped between
+// labels. The TPM key, scheme, AEAD and KEM settings are shared; Config.PQSeed is not,
+// as it would give every label the same key, so label keys are always random.
+//
+// Concurrency: each label has its own lock, so signing under different labels doesn't
+// contend; creating a label's file takes only that label's write lock.
+
+// SignPQB64ForLabel is SignPQB64 with label's key, creating it on first use.
+func (r *runtimeImpl) SignPQB64ForLabel(ctx context.Context, label string, msg []byte) (string, error) {
+	lr, err := r.forLabel(ctx, label)
+	if err != nil {
+		return "", err
+	}
+	return lr.SignPQB64(ctx, msg)
+}
+
+// PQPublicKeyB64ForLabel is PQPublicKeyB64 for label's key, creating it on first use.
+func (r *runtimeImpl) PQPublicKeyB64ForLabel(ctx context.Context, label string) (string, error) {
+	lr, err := r.forLabel(ctx, label)
+	if err != nil {
+		return "", err
+	}
+	return lr.PQPublicKeyB64(ctx)
+}
+
+// forLabel returns the runtime view for label, ensuring its key file exists.
+func (r *runtimeImpl) forLabel(ctx context.Context, label string) (*runtimeImpl, error) {
+	if r == nil {
+		return nil, fmt.Errorf("cryptoctx: runtime is nil")
+	}
+	if label == r.pqLabel {
+		return r, nil
+	}
+	if err := checkLabel(label); err != nil {
+		return nil, err
+	}
+
+	r.labelsMu.Lock()
+	lr, ok := r.labels[label]
+	if !ok {
+		path := labelKeyPath(r.pqPath, label)
+		lr = r.derive(r.scheme, path, defaultKEMKeyPath(path), label)
+		if r.labels == nil {
+			r.labels = make(map[string]*runtimeImpl)
+		}
+		r.labels[label] = lr
+	}
+	r.labelsMu.Unlock()
+
+	if err := lr.EnsurePQKeypair(ctx); err != nil {
+		return nil, fmt.Errorf("cryptoctx: PQ key for label %q: %w", label, err)
+	}
+	return lr, nil
+}
+
+// derive returns a runtime sharing r's TPM, sealer and settings but with its own key
+// file, lock and (always random) keygen. It does not own the TPM client; never Close it.
+func (r *runtimeImpl) derive(scheme sign.Scheme, pqPath, kemKeyPath, label string) *runtimeImpl {
+	return &runtimeImpl{
+		tpm:        r.tpm,
+		sealer:     r.sealer,
+		scheme:     scheme,
+		kem:        r.kem,
+		kemKeyPath: kemKeyPath,
+		pqPath:     pqPath,
+		pqLabel:    label,
+		aead:       r.aead,
+		prevGrace:  r.prevGrace,
+		tpmPubB64:  r.tpmPubB64,
+		onSign:     r.onSign,
+		now:        r.now,
+	}
+}
+
+// labelKeyPath ends in ".enc" so no label's file can be another label's ".kem" file.
+func labelKeyPath(pqPath, label string) string {
+	return pqPath + ".label-" + label + ".enc"
+}
+
+func checkLabel(label string) error {
+	if len(label) == 0 || len(label) > 64 {
+		return ErrInvalidLabel
+	}
+	for _, c := range label {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '.', c == '_', c == '-':
+		default:
+			return ErrInvalidLabel
+		}
+	}
+	if label == "." || label == ".." {
+		return ErrInvalidLabel
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package cryptoctx
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+)
+
+func TestLabelKeyFilesFollowKeyFile(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	var pubs []string
+	for _, name := range []string{"a.json.enc", "b.json.enc"} {
+		rt, err := NewInMemory(ctx, Config{PQKeyFilePath: filepath.Join(dir, name), PQLabel: "default"})
+		if err != nil {
+			t.Fatalf("NewInMemory(%s): %v", name, err)
+		}
+		pub, err := rt.(*runtimeImpl).PQPublicKeyB64ForLabel(ctx, "tenant")
+		if err != nil {
+			t.Fatalf("PQPublicKeyB64ForLabel(%s): %v", name, err)
+		}
+		pubs = append(pubs, pub)
+	}
+	if pubs[0] == pubs[1] {
+		t.Fatal("runtimes with different key files share a label key")
+	}
+}
+
+func TestLabelKeyPathAvoidsKEMFiles(t *testing.T) {
+	p := "/keys/pqkeys.json.enc"
+	if got, other := labelKeyPath(p, "x.kem"), defaultKEMKeyPath(labelKeyPath(p, "x")); got == other {
+		t.Fatalf("label %q's key file is label %q's KEM file: %s", "x.kem", "x", got)
+	}
+}
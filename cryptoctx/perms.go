@@ -0,0 +1,70 @@
+package cryptoctx
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// FilePermsPolicy says what New does when a key file is accessible to group or others,
+// or its directory is writable by them, e.g. because an older version or a loose umask
+// created it.
+type FilePermsPolicy int
+
+const (
+	// FilePermsFix removes the offending bits with chmod (default).
+	FilePermsFix FilePermsPolicy = iota
+	// FilePermsStrict refuses to start with ErrInsecurePermissions.
+	FilePermsStrict
+	// FilePermsIgnore skips the check.
+	FilePermsIgnore
+)
+
+var ErrInsecurePermissions = errors.New("cryptoctx: key file permissions too open")
+
+// checkKeyFilePerms applies policy to the PQ key file, the KEM key file (if any) and
+// the directory holding them. Missing files are skipped. Unix permission bits don't
+// apply on Windows, so the check is a no-op there.
+func (r *runtimeImpl) checkKeyFilePerms(policy FilePermsPolicy) error {
+	if policy == FilePermsIgnore || runtime.GOOS == "windows" {
+		return nil
+	}
+
+	// The directory may be shared (e.g. /etc/<app>), so only group/other write access,
+	// which would let someone swap the key file, is flagged there.
+	type target struct {
+		path      string
+		forbidden os.FileMode
+	}
+	targets := []target{
+		{filepath.Dir(r.pqPath), 0o022},
+		{r.pqPath, 0o077},
+	}
+	if r.kem != nil {
+		targets = append(targets, target{r.kemKeyPath, 0o077})
+	}
+
+	for _, t := range targets {
+		fi, err := os.Stat(t.path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("cryptoctx: stat %s: %w", t.path, err)
+		}
+
+		mode := fi.Mode().Perm()
+		if mode&t.forbidden == 0 {
+			continue
+		}
+		if policy == FilePermsStrict {
+			return fmt.Errorf("%w: %s is %04o, want none of %04o", ErrInsecurePermissions, t.path, mode, t.forbidden)
+		}
+		if err := os.Chmod(t.path, mode&^t.forbidden); err != nil {
+			return fmt.Errorf("cryptoctx: tighten permissions of %s (%04o): %w", t.path, mode, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package cryptoctx
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/quantumauth-io/quantum-go-utils/qa/requests"
+)
+
+// SignedRequest is a QuantumAuth request signature: the exact canonical string that was
+// signed, both signatures over it and the keys to verify them with.
+type SignedRequest struct {
+	Canonical string
+
+	TPMSignatureB64 string
+	PQSignatureB64  string
+
+	TPMPublicKeyB64 string
+	PQPublicKeyB64  string
+	PQScheme        string
+}
+
+// SignCanonicalRequest builds the canonical string for ci (requests.CanonicalString) and
+// signs those bytes with the TPM and PQ keys, so what is signed is exactly what a
+// verifier rebuilds from the X-QA-* headers.
+func (r *runtimeImpl) SignCanonicalRequest(ctx context.Context, ci requests.CanonicalInput) (*SignedRequest, error) {
+	canonical, err := requests.CanonicalString(ci)
+	if err != nil {
+		return nil, fmt.Errorf("cryptoctx: canonical request: %w", err)
+	}
+	msg := []byte(canonical)
+
+	tpmSig, err := r.SignTPMB64(ctx, msg)
+	if err != nil {
+		return nil, fmt.Errorf("cryptoctx: TPM sign request: %w", err)
+	}
+	pqSig, err := r.SignPQB64(ctx, msg)
+	if err != nil {
+		return nil, fmt.Errorf("cryptoctx: PQ sign request: %w", err)
+	}
+	pqPub, err := r.PQPublicKeyB64(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SignedRequest{
+		Canonical:       canonical,
+		TPMSignatureB64: tpmSig,
+		PQSignatureB64:  pqSig,
+		TPMPublicKeyB64: r.tpmPubB64,
+		PQPublicKeyB64:  pqPub,
+		PQScheme:        r.scheme.Name(),
+	}, nil
+}
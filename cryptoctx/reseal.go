@@ -0,0 +1,100 @@
+package cryptoctx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ExportPQKeyFileDEK returns the key the PQ key file ciphertext is encrypted under (for v2
+// envelopes, the derived hybrid DEK), for escrow while the TPM still unseals it. It is the
+// priorDEK ResealToCurrentTPM takes after a TPM swap. Every rewrite of the file
+// (RotatePQKeypair, ResealToCurrentTPM) uses a fresh DEK, so export again after those.
+// Whoever holds the DEK and the file can read the PQ private key; keep it accordingly.
+func (r *runtimeImpl) ExportPQKeyFileDEK(ctx context.Context) ([]byte, error) {
+	if r == nil {
+		return nil, fmt.Errorf("cryptoctx: runtime is nil")
+	}
+
+	r.keyMu.RLock()
+	defer r.keyMu.RUnlock()
+
+	env, nonce, ct, err := r.readEnvelope()
+	if err != nil {
+		return nil, err
+	}
+	dek, err := r.unsealDEK(ctx, env)
+	if err != nil {
+		return nil, err
+	}
+	kp, err := r.openPayload(env.AEAD, dek, nonce, ct)
+	if err != nil {
+		zeroBytes(dek)
+		return nil, err
+	}
+	kp.zeroize()
+	return dek, nil
+}
+
+// ResealToCurrentTPM recovers the PQ key file after a TPM swap. The old TPM can no longer
+// unseal the envelope, so the caller supplies priorDEK, the key the envelope ciphertext was
+// encrypted under, as escrowed from ExportPQKeyFileDEK.
+//
+// The keypair is decrypted with priorDEK, checked for consistency and rewritten under a
+// fresh DEK sealed to the present TPM. The PQ identity is unchanged; use this instead of
+// deleting the file, which would generate a new key. With a KEM configured, a KEM key
+// file sealed by the old TPM is replaced by a fresh KEM key; the envelope is encapsulated
+// anew anyway, so only envelopes not yet resealed still depend on the old one.
+func (r *runtimeImpl) ResealToCurrentTPM(ctx context.Context, priorDEK []byte) error {
+	if r == nil {
+		return fmt.Errorf("cryptoctx: runtime is nil")
+	}
+	if len(priorDEK) != 32 {
+		return fmt.Errorf("cryptoctx: prior DEK must be 32 bytes, got %d", len(priorDEK))
+	}
+
+	r.keyMu.Lock()
+	defer r.keyMu.Unlock()
+
+	env, nonce, ct, err := r.readEnvelope()
+	if err != nil {
+		return err
+	}
+
+	kp, err := r.openPayload(env.AEAD, priorDEK, nonce, ct)
+	if err != nil {
+		return fmt.Errorf("cryptoctx: prior DEK does not open the PQ key file: %w", err)
+	}
+	defer kp.zeroize()
+
+	if err := r.checkPQKeypair(kp); err != nil {
+		return err
+	}
+	if r.kem != nil {
+		if _, err := r.loadOrCreateKEMKey(ctx); errors.Is(err, errKEMKeyUnsealable) {
+			if _, err := r.createKEMKey(ctx); err != nil {
+				return err
+			}
+		} else if err != nil {
+			return err
+		}
+	}
+
+	return r.writeEncryptedPQKeypair(ctx, *kp, env.keyMeta())
+}
+
+// checkPQKeypair verifies that the private key belongs to the stored public key.
+func (r *runtimeImpl) checkPQKeypair(kp *pqKeypair) error {
+	sk, err := r.scheme.UnmarshalBinaryPrivateKey(kp.Priv)
+	if err != nil {
+		return fmt.Errorf("cryptoctx: unmarshal PQ private key: %w", err)
+	}
+	pk, err := r.scheme.UnmarshalBinaryPublicKey(kp.Pub)
+	if err != nil {
+		return fmt.Errorf("cryptoctx: unmarshal PQ public key: %w", err)
+	}
+	if !pk.Equal(sk.Public()) {
+		return fmt.Errorf("cryptoctx: PQ private key does not match stored public key")
+	}
+	return nil
+}
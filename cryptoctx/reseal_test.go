@@ -0,0 +1,71 @@
+package cryptoctx
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestResealToCurrentTPM(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		kem  string
+	}{
+		{"v1", ""},
+		{"v2 hybrid", "ML-KEM-768"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			cfg := Config{
+				PQKeyFilePath:   filepath.Join(t.TempDir(), "pqkeys.json.enc"),
+				PQLabel:         "test",
+				PQKEMSchemeName: tc.kem,
+			}
+			old, err := newRuntime(ctx, cfg, sharedMemOpen(t))
+			if err != nil {
+				t.Fatalf("newRuntime: %v", err)
+			}
+			pub, err := old.PQPublicKeyB64(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			dek, err := old.ExportPQKeyFileDEK(ctx)
+			if err != nil {
+				t.Fatalf("ExportPQKeyFileDEK: %v", err)
+			}
+
+			// A new TPM: same files, a sealer that can't unseal what the old one sealed.
+			swapped, err := newRuntime(ctx, cfg, sharedMemOpen(t))
+			if err != nil {
+				t.Fatalf("newRuntime after swap: %v", err)
+			}
+			if _, err := swapped.SignPQB64(ctx, []byte("msg")); !errors.Is(err, ErrCorruptOrTampered) {
+				t.Fatalf("SignPQB64 before reseal err = %v, want ErrCorruptOrTampered", err)
+			}
+
+			wrong := make([]byte, 32)
+			if err := swapped.ResealToCurrentTPM(ctx, wrong); !errors.Is(err, ErrCorruptOrTampered) {
+				t.Fatalf("ResealToCurrentTPM(wrong DEK) err = %v, want ErrCorruptOrTampered", err)
+			}
+			if err := swapped.ResealToCurrentTPM(ctx, dek); err != nil {
+				t.Fatalf("ResealToCurrentTPM: %v", err)
+			}
+
+			got, err := swapped.PQPublicKeyB64(ctx)
+			if err != nil {
+				t.Fatalf("PQPublicKeyB64 after reseal: %v", err)
+			}
+			if got != pub {
+				t.Fatal("reseal changed the PQ public key")
+			}
+			sig, err := swapped.SignPQB64(ctx, []byte("msg"))
+			if err != nil {
+				t.Fatalf("SignPQB64 after reseal: %v", err)
+			}
+			if ok, err := swapped.VerifyPQB64(ctx, []byte("msg"), sig); err != nil || !ok {
+				t.Fatalf("signature after reseal does not verify: ok=%t err=%v", ok, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package cryptoctx
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+const defaultPreviousKeyGrace = 7 * 24 * time.Hour
+
+// ErrNoPreviousPQKey is returned by PreviousPQPublicKeyB64 when no key has been rotated
+// out, or the grace period for the last one has passed.
+var ErrNoPreviousPQKey = errors.New("cryptoctx: no previous PQ public key")
+
+// previousPQKeyFile is the plaintext record of the public key replaced by the last
+// rotation. Public keys aren't secret, so it is not sealed.
+type previousPQKeyFile struct {
+	PubB64    string `json:"pub_b64"`
+	SigScheme string `json:"sig_scheme"`
+	RetiredAt string `json:"retired_at"` // RFC 3339, UTC
+}
+
+func (r *runtimeImpl) previousKeyPath() string {
+	return r.pqPath + ".prev"
+}
+
+// RotatePQKeypair replaces the PQ keypair with a freshly generated one, sealed under a
+// new DEK. The old public key stays available from PreviousPQPublicKeyB64 for
+// Config.PQPreviousKeyGrace so verifiers can still check in-flight signatures.
+//
+// Crash safety: the old public key is recorded first, then the envelope is swapped with
+// an atomic rename. A crash in between leaves the old key in place and recorded as
+// "previous" too, which is harmless; rotating again completes the job.
+//
+// A seed-derived key (Config.PQSeed) can't be rotated: the seed would no longer
+// recover the new key.
+func (r *runtimeImpl) RotatePQKeypair(ctx context.Context) error {
+	if r == nil {
+		return fmt.Errorf("cryptoctx: runtime is nil")
+	}
+	if len(r.pqSeed) > 0 {
+		return fmt.Errorf("cryptoctx: cannot rotate a PQ key derived from Config.PQSeed")
+	}
+
+	r.keyMu.Lock()
+	defer r.keyMu.Unlock()
+
+	env, _, _, err := r.readEnvelope()
+	if err != nil {
+		return fmt.Errorf("cryptoctx: rotate: read current key: %w", err)
+	}
+	old, err := r.decryptPQKeypair(ctx)
+	if err != nil {
+		return fmt.Errorf("cryptoctx: rotate: read current key: %w", err)
+	}
+	prev, err := json.MarshalIndent(previousPQKeyFile{
+		PubB64:    base64.RawStdEncoding.EncodeToString(old.Pub),
+		SigScheme: r.scheme.Name(),
+		RetiredAt: r.now().UTC().Format(time.RFC3339),
+	}, "", "  ")
+	old.zeroize()
+	if err != nil {
+		return fmt.Errorf("cryptoctx: marshal previous key: %w", err)
+	}
+	if err := atomicWriteFile(r.previousKeyPath(), prev, 0o600); err != nil {
+		return fmt.Errorf("cryptoctx: rotate: record previous key: %w", err)
+	}
+
+	pk, sk, err := r.scheme.GenerateKey()
+	if err != nil {
+		return fmt.Errorf("cryptoctx: PQ keygen failed: %w", err)
+	}
+	kp, err := marshalPQKeypair(pk, sk)
+	if err != nil {
+		return err
+	}
+	defer kp.zeroize()
+
+	prevMeta := env.keyMeta()
+	if err := r.writeEncryptedPQKeypair(ctx, *kp, r.newKeyMeta(&prevMeta)); err != nil {
+		return fmt.Errorf("cryptoctx: rotate: %w", err)
+	}
+	return nil
+}
+
+// PreviousPQPublicKeyB64 returns the public key replaced by the last RotatePQKeypair and
+// when it was retired, or ErrNoPreviousPQKey once Config.PQPreviousKeyGrace has passed.
+func (r *runtimeImpl) PreviousPQPublicKeyB64(ctx context.Context) (string, time.Time, error) {
+	_ = ctx
+	if r == nil {
+		return "", time.Time{}, fmt.Errorf("cryptoctx: runtime is nil")
+	}
+
+	r.keyMu.RLock()
+	defer r.keyMu.RUnlock()
+
+	b, err := os.ReadFile(r.previousKeyPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", time.Time{}, ErrNoPreviousPQKey
+		}
+		return "", time.Time{}, fmt.Errorf("cryptoctx: read previous key: %w", err)
+	}
+
+	var prev previousPQKeyFile
+	if err := json.Unmarshal(b, &prev); err != nil {
+		return "", time.Time{}, fmt.Errorf("cryptoctx: unmarshal previous key: %w", err)
+	}
+	retiredAt, err := time.Parse(time.RFC3339, prev.RetiredAt)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("cryptoctx: previous key retired_at: %w", err)
+	}
+	if prev.SigScheme != r.scheme.Name() || r.now().After(retiredAt.Add(r.prevGrace)) {
+		return "", time.Time{}, ErrNoPreviousPQKey
+	}
+	return prev.PubB64, retiredAt, nil
+}
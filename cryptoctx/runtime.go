@@ -9,12 +9,15 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
 	"time"
 
+	"github.com/cloudflare/circl/kem"
+	kemschemes "github.com/cloudflare/circl/kem/schemes"
 	"github.com/cloudflare/circl/sign"
 	"github.com/cloudflare/circl/sign/schemes"
-	"golang.org/x/crypto/chacha20poly1305"
 
+	"github.com/quantumauth-io/quantum-go-utils/qa/requests"
 	"github.com/quantumauth-io/quantum-go-utils/tpmdevice"
 )
 
@@ -22,6 +25,7 @@ var (
 	ErrCorruptOrTampered   = errors.New("cryptoctx: corrupt or tampered key file")
 	ErrMissingPQKeyFile    = errors.New("cryptoctx: PQ key file missing")
 	ErrMissingTPMPublicKey = errors.New("cryptoctx: TPM public key missing")
+	ErrTPMKeyAuthMissing   = errors.New("cryptoctx: TPM key requires auth but no TPMKeyAuth or TPM.KeyAuth is configured")
 )
 
 type Runtime interface {
@@ -30,8 +34,26 @@ type Runtime interface {
 
 	SignTPMB64(ctx context.Context, msg []byte) (string, error)
 	SignPQB64(ctx context.Context, msg []byte) (string, error)
+	VerifyPQB64(ctx context.Context, msg []byte, sigB64 string) (bool, error)
+	SignPQB64ForLabel(ctx context.Context, label string, msg []byte) (string, error)
+	PQPublicKeyB64ForLabel(ctx context.Context, label string) (string, error)
+	SignHybridB64(ctx context.Context, msg []byte) (tpmSigB64, pqSigB64 string, err error)
+	SupportedSchemes() []string
+	KeyInfo(ctx context.Context) (KeyInfo, error)
+	SignPQB64WithScheme(ctx context.Context, schemeName string, msg []byte) (string, error)
+	PQPublicKeyB64ForScheme(ctx context.Context, schemeName string) (string, error)
+	SignTPMDomainB64(ctx context.Context, domain string, msg []byte) (string, error)
+	SignPQDomainB64(ctx context.Context, domain string, msg []byte) (string, error)
+	SignCanonicalRequest(ctx context.Context, ci requests.CanonicalInput) (*SignedRequest, error)
 
 	EnsurePQKeypair(ctx context.Context) error
+	EnrollmentBundle(ctx context.Context, nonce []byte) (*Bundle, error)
+	HealthCheck(ctx context.Context) error
+	Status(ctx context.Context) (*RuntimeStatus, error)
+	ExportPQKeyFileDEK(ctx context.Context) ([]byte, error)
+	ResealToCurrentTPM(ctx context.Context, priorDEK []byte) error
+	RotatePQKeypair(ctx context.Context) error
+	PreviousPQPublicKeyB64(ctx context.Context) (string, time.Time, error)
 	Close() error
 }
 
@@ -42,6 +64,14 @@ type Config struct {
 	// TPM owner auth (often empty on dev machines)
 	OwnerAuth string
 
+	// PIN source for a TPM key gated by auth (created with TPM.KeyAuth, see
+	// tpmdevice.Client.RequiresAuth), e.g. a user prompt. Called for every TPM signature.
+	// If nil, TPM.KeyAuth is used; New fails for such a key when both are empty.
+	TPMKeyAuth func(ctx context.Context) (string, error)
+
+	// Storage parent used to seal the DEK (default: ECC P-256)
+	SealingParent tpmdevice.SealingParent
+
 	// PQ key storage
 	PQKeyFilePath string // if empty, uses default in user config dir
 	PQLabel       string // required; scopes DEK sealing/unsealing
@@ -49,21 +79,93 @@ type Config struct {
 	// CIRCL scheme name
 	PQSchemeName string // default: "ML-DSA-65"
 
+	// Optional schemes still accepted while migrating away from them (see scheme.go).
+	// Their keys are kept for SignPQB64WithScheme; SignPQB64 always uses PQSchemeName.
+	PQAcceptSchemeNames []string
+
+	// Cipher for newly written key files (default: AEADXChaCha20Poly1305). Existing
+	// files are read with the cipher recorded in them.
+	AEAD AEAD
+
+	// Optional seed for deterministic PQ key generation (scheme.SeedSize() bytes, 32 for
+	// ML-DSA). Only used when EnsurePQKeypair creates the key file; the same seed always
+	// yields the same keypair, so keep it as secret as the key itself.
+	PQSeed []byte
+
+	// Optional CIRCL KEM (e.g. "ML-KEM-768"). When set, the DEK needs both the
+	// TPM seal and a KEM decapsulation to unwrap.
+	PQKEMSchemeName  string
+	PQKEMKeyFilePath string // if empty, PQKeyFilePath + ".kem"
+
+	// Optional audit hook, called after every successful SignTPMB64 / SignPQB64 with
+	// keyType SignKeyTPM or SignKeyPQ. It only ever sees the message length, never key material.
+	OnSign func(ctx context.Context, keyType string, msgLen int, at time.Time)
+
+	// How long PreviousPQPublicKeyB64 keeps returning the key replaced by
+	// RotatePQKeypair (default: 7 days)
+	PQPreviousKeyGrace time.Duration
+
+	// What to do about key files readable by group/others (default: FilePermsFix)
+	FilePerms FilePermsPolicy
+
 	// Optional tuning
 	Now func() time.Time
 }
 
+// Key types reported to Config.OnSign.
+const (
+	SignKeyTPM = "tpm"
+	SignKeyPQ  = "pq"
+)
+
+// Concurrency: a Runtime is safe for concurrent use. Signing and other reads of the PQ
+// key file share a read lock; EnsurePQKeypair and ResealToCurrentTPM take the write
+// lock, so in-process readers never see a half-replaced key. The file is replaced by an
+// atomic rename; a reader in another process that loses the race with a rename retries
+// once against the new file.
 type runtimeImpl struct {
-	tpm       tpmdevice.Client
-	sealer    tpmdevice.Sealer
-	scheme    sign.Scheme
-	pqPath    string
-	pqLabel   string
-	tpmPubB64 string
-	now       func() time.Time
+	keyMu sync.RWMutex // guards the PQ key file
+
+	tpm        tpmdevice.Client
+	sealer     tpmdevice.Sealer
+	scheme     sign.Scheme
+	kem        kem.Scheme // nil = TPM seal only
+	kemKeyPath string
+	pqPath     string
+	pqLabel    string
+	pqSeed     []byte // nil = random keygen
+	aead       AEAD
+	prevGrace  time.Duration
+	tpmPubB64  string
+	tpmAuth    func(ctx context.Context) (string, error) // nil = key has no auth
+	onSign     func(ctx context.Context, keyType string, msgLen int, at time.Time)
+	now        func() time.Time
+
+	accept      map[string]*runtimeImpl // accepted non-primary schemes by name; see scheme.go
+	acceptNames []string
+
+	labelsMu sync.Mutex
+	labels   map[string]*runtimeImpl // keyring views by label; see keyring.go
 }
 
 func New(ctx context.Context, cfg Config) (Runtime, error) {
+	rt, err := newRuntime(ctx, cfg, func() (tpmdevice.Client, tpmdevice.Sealer, error) {
+		// TPM signer (persistent ECC key)
+		tpmClient, err := tpmdevice.NewWithConfig(ctx, cfg.TPM)
+		if err != nil {
+			return nil, nil, err
+		}
+		return tpmClient, tpmdevice.NewSealerWithParent(cfg.OwnerAuth, cfg.SealingParent), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rt, nil
+}
+
+// newRuntime validates cfg, then opens the signer and sealer and builds the runtime.
+// It owns the returned client: on any later error it is closed.
+func newRuntime(ctx context.Context, cfg Config, open func() (tpmdevice.Client, tpmdevice.Sealer, error)) (*runtimeImpl, error) {
 	now := cfg.Now
 	if now == nil {
 		now = time.Now
@@ -78,9 +180,26 @@ func New(ctx context.Context, cfg Config) (Runtime, error) {
 	if scheme == nil {
 		return nil, fmt.Errorf("cryptoctx: PQ scheme %q not found", schemeName)
 	}
+	aeadAlg := cfg.AEAD
+	if aeadAlg == "" {
+		aeadAlg = AEADXChaCha20Poly1305
+	}
+	if _, err := newAEAD(aeadAlg, make([]byte, 32)); err != nil {
+		return nil, err
+	}
+	if cfg.PQSeed != nil && len(cfg.PQSeed) != scheme.SeedSize() {
+		return nil, fmt.Errorf("cryptoctx: PQ seed for %s must be %d bytes, got %d", scheme.Name(), scheme.SeedSize(), len(cfg.PQSeed))
+	}
+
+	var kemScheme kem.Scheme
+	if cfg.PQKEMSchemeName != "" {
+		kemScheme = kemschemes.ByName(cfg.PQKEMSchemeName)
+		if kemScheme == nil {
+			return nil, fmt.Errorf("cryptoctx: PQ KEM scheme %q not found", cfg.PQKEMSchemeName)
+		}
+	}
 
-	// TPM signer (persistent ECC key)
-	tpmClient, err := tpmdevice.NewWithConfig(ctx, cfg.TPM)
+	tpmClient, sealer, err := open()
 	if err != nil {
 		return nil, err
 	}
@@ -91,6 +210,19 @@ func New(ctx context.Context, cfg Config) (Runtime, error) {
 		return nil, ErrMissingTPMPublicKey
 	}
 
+	var tpmAuth func(ctx context.Context) (string, error)
+	if tpmClient.RequiresAuth() {
+		tpmAuth = cfg.TPMKeyAuth
+		if tpmAuth == nil && cfg.TPM.KeyAuth != "" {
+			keyAuth := cfg.TPM.KeyAuth
+			tpmAuth = func(context.Context) (string, error) { return keyAuth, nil }
+		}
+		if tpmAuth == nil {
+			_ = tpmClient.Close()
+			return nil, ErrTPMKeyAuthMissing
+		}
+	}
+
 	pqPath := cfg.PQKeyFilePath
 	if pqPath == "" {
 		pqPath, err = defaultPQPath()
@@ -105,16 +237,44 @@ func New(ctx context.Context, cfg Config) (Runtime, error) {
 		return nil, fmt.Errorf("cryptoctx: PQLabel is required")
 	}
 
-	sealer := tpmdevice.NewSealer(cfg.OwnerAuth)
+	prevGrace := cfg.PQPreviousKeyGrace
+	if prevGrace == 0 {
+		prevGrace = defaultPreviousKeyGrace
+	}
+
+	kemKeyPath := cfg.PQKEMKeyFilePath
+	if kemKeyPath == "" {
+		kemKeyPath = defaultKEMKeyPath(pqPath)
+	}
 
 	rt := &runtimeImpl{
-		tpm:       tpmClient,
-		sealer:    sealer,
-		scheme:    scheme,
-		pqPath:    pqPath,
-		pqLabel:   cfg.PQLabel,
-		tpmPubB64: tpmPub,
-		now:       now,
+		tpm:        tpmClient,
+		sealer:     sealer,
+		scheme:     scheme,
+		kem:        kemScheme,
+		kemKeyPath: kemKeyPath,
+		pqPath:     pqPath,
+		pqLabel:    cfg.PQLabel,
+		pqSeed:     append([]byte(nil), cfg.PQSeed...),
+		aead:       aeadAlg,
+		prevGrace:  prevGrace,
+		tpmPubB64:  tpmPub,
+		tpmAuth:    tpmAuth,
+		onSign:     cfg.OnSign,
+		now:        now,
+	}
+
+	if err := rt.initAcceptSchemes(cfg.PQAcceptSchemeNames); err != nil {
+		_ = rt.Close()
+		return nil, err
+	}
+	if err := rt.migrateScheme(ctx); err != nil {
+		_ = rt.Close()
+		return nil, err
+	}
+	if err := rt.checkKeyFileScheme(ctx); err != nil {
+		_ = rt.Close()
+		return nil, err
 	}
 
 	// Ensure file exists on first run
@@ -123,12 +283,18 @@ func New(ctx context.Context, cfg Config) (Runtime, error) {
 		return nil, err
 	}
 
+	if err := rt.checkKeyFilePerms(cfg.FilePerms); err != nil {
+		_ = rt.Close()
+		return nil, err
+	}
+
 	return rt, nil
 }
 func (r *runtimeImpl) Close() error {
 	if r == nil || r.tpm == nil {
 		return nil
 	}
+	zeroBytes(r.pqSeed)
 	return r.tpm.Close()
 }
 
@@ -140,11 +306,27 @@ func (r *runtimeImpl) TPMPublicKeyB64() string {
 }
 
 func (r *runtimeImpl) SignTPMB64(ctx context.Context, msg []byte) (string, error) {
-	_ = ctx // TPM signing doesn’t need ctx today; keep it for future
 	if r == nil || r.tpm == nil {
 		return "", fmt.Errorf("cryptoctx: TPM client not initialized")
 	}
-	return r.tpm.SignB64(msg)
+	sig, err := r.signTPM(ctx, msg)
+	if err != nil {
+		return "", err
+	}
+	r.auditSign(ctx, SignKeyTPM, msg)
+	return sig, nil
+}
+
+// signTPM signs with the TPM key, asking Config.TPMKeyAuth for the PIN if the key has one.
+func (r *runtimeImpl) signTPM(ctx context.Context, msg []byte) (string, error) {
+	if r.tpmAuth == nil {
+		return r.tpm.SignB64(msg)
+	}
+	auth, err := r.tpmAuth(ctx)
+	if err != nil {
+		return "", fmt.Errorf("cryptoctx: TPM key auth: %w", err)
+	}
+	return r.tpm.SignWithAuthB64(msg, auth)
 }
 
 func (r *runtimeImpl) PQPublicKeyB64(ctx context.Context) (string, error) {
@@ -171,14 +353,24 @@ func (r *runtimeImpl) SignPQB64(ctx context.Context, msg []byte) (string, error)
 	if sig == nil {
 		return "", fmt.Errorf("cryptoctx: PQ sign failed")
 	}
+	r.auditSign(ctx, SignKeyPQ, msg)
 	return base64.RawStdEncoding.EncodeToString(sig), nil
 }
 
+func (r *runtimeImpl) auditSign(ctx context.Context, keyType string, msg []byte) {
+	if r.onSign != nil {
+		r.onSign(ctx, keyType, len(msg), r.now())
+	}
+}
+
 func (r *runtimeImpl) EnsurePQKeypair(ctx context.Context) error {
 	if r == nil {
 		return fmt.Errorf("cryptoctx: runtime is nil")
 	}
 
+	r.keyMu.Lock()
+	defer r.keyMu.Unlock()
+
 	// If file exists, nothing to do.
 	if _, err := os.Stat(r.pqPath); err == nil {
 		return nil
@@ -191,50 +383,97 @@ func (r *runtimeImpl) EnsurePQKeypair(ctx context.Context) error {
 		return fmt.Errorf("cryptoctx: mkdir PQ dir: %w", err)
 	}
 
-	// Generate PQ keypair
-	pk, sk, err := r.scheme.GenerateKey()
-	if err != nil {
+	// Generate PQ keypair, from the seed when one is configured
+	var (
+		pk  sign.PublicKey
+		sk  sign.PrivateKey
+		err error
+	)
+	if len(r.pqSeed) > 0 {
+		pk, sk = r.scheme.DeriveKey(r.pqSeed)
+	} else if pk, sk, err = r.scheme.GenerateKey(); err != nil {
 		return fmt.Errorf("cryptoctx: PQ keygen failed: %w", err)
 	}
 
+	kp, err := marshalPQKeypair(pk, sk)
+	if err != nil {
+		return err
+	}
+	defer kp.zeroize()
+
+	if err := r.writeEncryptedPQKeypair(ctx, *kp, r.newKeyMeta(nil)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func marshalPQKeypair(pk sign.PublicKey, sk sign.PrivateKey) (*pqKeypair, error) {
 	pubBytes, err := pk.MarshalBinary()
 	if err != nil {
-		return fmt.Errorf("cryptoctx: marshal PQ pub: %w", err)
+		return nil, fmt.Errorf("cryptoctx: marshal PQ pub: %w", err)
 	}
 
 	privBytes, err := sk.MarshalBinary()
 	if err != nil {
-		return fmt.Errorf("cryptoctx: marshal PQ priv: %w", err)
+		return nil, fmt.Errorf("cryptoctx: marshal PQ priv: %w", err)
 	}
 
-	kp := pqKeypair{
+	return &pqKeypair{
 		Pub:  pubBytes,
 		Priv: privBytes,
-	}
-	defer kp.zeroize()
-
-	if err := r.writeEncryptedPQKeypair(ctx, kp); err != nil {
-		return err
-	}
-
-	return nil
+	}, nil
 }
 
 // ---------- file format + crypto ----------
 
 // v1 envelope: sealed DEK + XChaCha20-Poly1305 ciphertext of {pub,priv}
+// v2 envelope: as v1, but SealedDEK_B64 holds only the TPM share of the DEK;
+// the other share comes from decapsulating KEMCTB64 (see kem.go).
 type pqEnvelopeV1 struct {
 	V int `json:"v"`
 
-	// DEK sealed to this TPM (tpmdevice.Sealer)
+	// DEK (v1) or DEK TPM share (v2) sealed to this TPM (tpmdevice.Sealer)
 	SealedDEK_B64 string `json:"sealed_dek_b64"`
 
-	// AEAD
+	// v2 only
+	KEMScheme string `json:"kem_scheme,omitempty"`
+	KEMCTB64  string `json:"kem_ct_b64,omitempty"`
+
+	// AEAD; an empty AEAD field means XChaCha20-Poly1305
+	AEAD     AEAD   `json:"aead,omitempty"`
 	NonceB64 string `json:"nonce_b64"`
 	CTB64    string `json:"ct_b64"`
 
-	// Metadata
-	Label string `json:"label"`
+	// Metadata, plaintext so InspectEnvelope can read it without the TPM. Not covered
+	// by the AAD: informational only, never trusted when decrypting.
+	Label      string `json:"label"`
+	SigScheme  string `json:"sig_scheme,omitempty"`
+	CreatedAt  string `json:"created_at,omitempty"` // RFC 3339, UTC, when the key was generated
+	Generation uint64 `json:"generation,omitempty"` // 1 for the first key, +1 per rotation
+}
+
+// keyMeta is the key metadata carried into a rewritten envelope.
+type keyMeta struct {
+	CreatedAt  string
+	Generation uint64
+}
+
+// newKeyMeta returns the metadata for a key generated now, replacing prev (nil for the
+// first key). Files written before Generation existed count as generation 1.
+func (r *runtimeImpl) newKeyMeta(prev *keyMeta) keyMeta {
+	var gen uint64
+	if prev != nil {
+		gen = max(prev.Generation, 1)
+	}
+	return keyMeta{
+		CreatedAt:  r.now().UTC().Format(time.RFC3339),
+		Generation: gen + 1,
+	}
+}
+
+func (env *pqEnvelopeV1) keyMeta() keyMeta {
+	return keyMeta{CreatedAt: env.CreatedAt, Generation: env.Generation}
 }
 
 type pqPayloadV1 struct {
@@ -255,8 +494,10 @@ func (k *pqKeypair) zeroize() {
 	zeroBytes(k.Priv)
 }
 
-func (r *runtimeImpl) writeEncryptedPQKeypair(ctx context.Context, kp pqKeypair) error {
-	// random DEK (32 bytes for XChaCha20-Poly1305)
+// writeEncryptedPQKeypair encrypts kp under a fresh DEK and replaces the key file. meta
+// is recorded as is: new keys pass newKeyMeta, rewrites of the same key pass the old one.
+func (r *runtimeImpl) writeEncryptedPQKeypair(ctx context.Context, kp pqKeypair, meta keyMeta) error {
+	// random DEK (32 bytes for XChaCha20-Poly1305); with a KEM this is only the TPM share
 	dek := make([]byte, 32)
 	if _, err := rand.Read(dek); err != nil {
 		return fmt.Errorf("cryptoctx: rand dek: %w", err)
@@ -268,6 +509,28 @@ func (r *runtimeImpl) writeEncryptedPQKeypair(ctx context.Context, kp pqKeypair)
 		return fmt.Errorf("cryptoctx: seal dek: %w", err)
 	}
 
+	env := pqEnvelopeV1{
+		V:             1,
+		SealedDEK_B64: base64.StdEncoding.EncodeToString(sealed),
+		Label:         r.pqLabel,
+		SigScheme:     r.scheme.Name(),
+		CreatedAt:     meta.CreatedAt,
+		Generation:    meta.Generation,
+	}
+
+	if r.kem != nil {
+		hybridDEK, kemCT, err := r.encapsulateDEK(ctx, dek)
+		if err != nil {
+			return err
+		}
+		defer zeroBytes(hybridDEK)
+		dek = hybridDEK
+
+		env.V = 2
+		env.KEMScheme = r.kem.Name()
+		env.KEMCTB64 = base64.StdEncoding.EncodeToString(kemCT)
+	}
+
 	payloadBytes, err := json.Marshal(pqPayloadV1{
 		Pub:  kp.Pub,
 		Priv: kp.Priv,
@@ -276,12 +539,12 @@ func (r *runtimeImpl) writeEncryptedPQKeypair(ctx context.Context, kp pqKeypair)
 		return fmt.Errorf("cryptoctx: marshal payload: %w", err)
 	}
 
-	aead, err := chacha20poly1305.NewX(dek)
+	aead, err := newAEAD(r.aead, dek)
 	if err != nil {
 		return fmt.Errorf("cryptoctx: aead: %w", err)
 	}
 
-	nonce := make([]byte, chacha20poly1305.NonceSizeX)
+	nonce := make([]byte, aead.NonceSize())
 	if _, err := rand.Read(nonce); err != nil {
 		return fmt.Errorf("cryptoctx: rand nonce: %w", err)
 	}
@@ -291,13 +554,9 @@ func (r *runtimeImpl) writeEncryptedPQKeypair(ctx context.Context, kp pqKeypair)
 
 	ct := aead.Seal(nil, nonce, payloadBytes, aad)
 
-	env := pqEnvelopeV1{
-		V:             1,
-		SealedDEK_B64: base64.StdEncoding.EncodeToString(sealed),
-		NonceB64:      base64.StdEncoding.EncodeToString(nonce),
-		CTB64:         base64.StdEncoding.EncodeToString(ct),
-		Label:         r.pqLabel,
-	}
+	env.AEAD = r.aead
+	env.NonceB64 = base64.StdEncoding.EncodeToString(nonce)
+	env.CTB64 = base64.StdEncoding.EncodeToString(ct)
 
 	out, err := json.MarshalIndent(env, "", "  ")
 	if err != nil {
@@ -308,37 +567,50 @@ func (r *runtimeImpl) writeEncryptedPQKeypair(ctx context.Context, kp pqKeypair)
 }
 
 func (r *runtimeImpl) loadPQKeypair(ctx context.Context) (*pqKeypair, error) {
-	b, err := os.ReadFile(r.pqPath)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return nil, ErrMissingPQKeyFile
+	r.keyMu.RLock()
+	defer r.keyMu.RUnlock()
+	return r.loadPQKeypairLocked(ctx)
+}
+
+// loadPQKeypairLocked is loadPQKeypair for callers already holding keyMu.
+func (r *runtimeImpl) loadPQKeypairLocked(ctx context.Context) (*pqKeypair, error) {
+	before, _ := os.Stat(r.pqPath)
+	kp, err := r.decryptPQKeypair(ctx)
+	if errors.Is(err, ErrCorruptOrTampered) && before != nil {
+		// Another process may have renamed a new file into place between our stat and read.
+		if after, statErr := os.Stat(r.pqPath); statErr == nil && !os.SameFile(before, after) {
+			return r.decryptPQKeypair(ctx)
 		}
-		return nil, fmt.Errorf("cryptoctx: read PQ key file: %w", err)
 	}
+	return kp, err
+}
 
-	var env pqEnvelopeV1
-	if err := json.Unmarshal(b, &env); err != nil {
-		return nil, fmt.Errorf("cryptoctx: unmarshal envelope: %w", err)
+func (r *runtimeImpl) decryptPQKeypair(ctx context.Context) (*pqKeypair, error) {
+	env, nonce, ct, err := r.readEnvelope()
+	if err != nil {
+		return nil, err
 	}
-	if env.V != 1 {
-		return nil, fmt.Errorf("cryptoctx: unsupported pq envelope version: %d", env.V)
+	dek, err := r.unsealDEK(ctx, env)
+	if err != nil {
+		return nil, err
 	}
-	if env.Label != "" && env.Label != r.pqLabel {
-		return nil, ErrCorruptOrTampered
+	defer zeroBytes(dek)
+
+	return r.openPayload(env.AEAD, dek, nonce, ct)
+}
+
+// unsealDEK returns the key the envelope ciphertext is encrypted under: the TPM-sealed DEK,
+// or for v2 envelopes the hybrid DEK derived from it and the KEM ciphertext. The caller
+// zeroes it.
+func (r *runtimeImpl) unsealDEK(ctx context.Context, env *pqEnvelopeV1) ([]byte, error) {
+	if env.V == 2 && (r.kem == nil || env.KEMScheme != r.kem.Name()) {
+		return nil, fmt.Errorf("cryptoctx: PQ key file requires KEM %q", env.KEMScheme)
 	}
 
 	sealed, err := base64.StdEncoding.DecodeString(env.SealedDEK_B64)
 	if err != nil {
 		return nil, ErrCorruptOrTampered
 	}
-	nonce, err := base64.StdEncoding.DecodeString(env.NonceB64)
-	if err != nil {
-		return nil, ErrCorruptOrTampered
-	}
-	ct, err := base64.StdEncoding.DecodeString(env.CTB64)
-	if err != nil {
-		return nil, ErrCorruptOrTampered
-	}
 
 	dek, err := r.sealer.Unseal(ctx, r.pqLabel, sealed)
 	if err != nil || len(dek) != 32 {
@@ -347,17 +619,68 @@ func (r *runtimeImpl) loadPQKeypair(ctx context.Context) (*pqKeypair, error) {
 		}
 		return nil, ErrCorruptOrTampered
 	}
+	if env.V != 2 {
+		return dek, nil
+	}
 	defer zeroBytes(dek)
 
-	aead, err := chacha20poly1305.NewX(dek)
+	kemCT, err := base64.StdEncoding.DecodeString(env.KEMCTB64)
+	if err != nil {
+		return nil, ErrCorruptOrTampered
+	}
+	return r.decapsulateDEK(ctx, dek, kemCT)
+}
+
+// readEnvelope reads and sanity-checks the key file, returning the AEAD nonce and ciphertext.
+func (r *runtimeImpl) readEnvelope() (*pqEnvelopeV1, []byte, []byte, error) {
+	b, err := os.ReadFile(r.pqPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil, nil, ErrMissingPQKeyFile
+		}
+		return nil, nil, nil, fmt.Errorf("cryptoctx: read PQ key file: %w", err)
+	}
+
+	var env pqEnvelopeV1
+	if err := json.Unmarshal(b, &env); err != nil {
+		return nil, nil, nil, fmt.Errorf("cryptoctx: unmarshal envelope: %w", err)
+	}
+	if env.V != 1 && env.V != 2 {
+		return nil, nil, nil, fmt.Errorf("cryptoctx: unsupported pq envelope version: %d", env.V)
+	}
+	if env.Label != "" && env.Label != r.pqLabel {
+		return nil, nil, nil, ErrCorruptOrTampered
+	}
+	if env.SigScheme != "" && env.SigScheme != r.scheme.Name() {
+		return nil, nil, nil, fmt.Errorf("%w: file has %s, want %s", ErrPQSchemeMismatch, env.SigScheme, r.scheme.Name())
+	}
+
+	nonce, err := base64.StdEncoding.DecodeString(env.NonceB64)
+	if err != nil {
+		return nil, nil, nil, ErrCorruptOrTampered
+	}
+	ct, err := base64.StdEncoding.DecodeString(env.CTB64)
+	if err != nil {
+		return nil, nil, nil, ErrCorruptOrTampered
+	}
+	return &env, nonce, ct, nil
+}
+
+// openPayload decrypts the envelope ciphertext with the final AEAD key.
+func (r *runtimeImpl) openPayload(alg AEAD, dek, nonce, ct []byte) (*pqKeypair, error) {
+	aead, err := newAEAD(alg, dek)
 	if err != nil {
 		return nil, fmt.Errorf("cryptoctx: aead: %w", err)
 	}
+	if len(nonce) != aead.NonceSize() {
+		return nil, ErrCorruptOrTampered
+	}
 
 	plain, err := aead.Open(nil, nonce, ct, r.aad())
 	if err != nil {
 		return nil, ErrCorruptOrTampered
 	}
+	defer zeroBytes(plain)
 
 	var payload pqPayloadV1
 	if err := json.Unmarshal(plain, &payload); err != nil {
@@ -392,17 +715,37 @@ func defaultPQPath() (string, error) {
 	return filepath.Join(dir, "quantumauth", "pqkeys.json.enc"), nil
 }
 
+// atomicWriteFile writes to a unique temp file next to path and renames it into place,
+// so concurrent writers (even in different processes) can't clobber each other's temp file.
 func atomicWriteFile(path string, data []byte, perm os.FileMode) error {
-	tmp := path + ".tmp"
-	_ = os.Remove(tmp)
-
 	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
 		return fmt.Errorf("cryptoctx: mkdir: %w", err)
 	}
 
-	if err := os.WriteFile(tmp, data, perm); err != nil {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("cryptoctx: create tmp: %w", err)
+	}
+	tmp := f.Name()
+	if err := f.Chmod(perm); err != nil {
+		_ = f.Close()
+		_ = os.Remove(tmp)
+		return fmt.Errorf("cryptoctx: chmod tmp: %w", err)
+	}
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		_ = os.Remove(tmp)
 		return fmt.Errorf("cryptoctx: write tmp: %w", err)
 	}
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		_ = os.Remove(tmp)
+		return fmt.Errorf("cryptoctx: sync tmp: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("cryptoctx: close tmp: %w", err)
+	}
 	if err := os.Rename(tmp, path); err != nil {
 		_ = os.Remove(tmp)
 		return fmt.Errorf("cryptoctx: rename: %w", err)
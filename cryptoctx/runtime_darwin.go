@@ -5,6 +5,9 @@ package cryptoctx
 import (
 	"context"
 	"fmt"
+	"time"
+
+	"github.com/quantumauth-io/quantum-go-utils/qa/requests"
 )
 
 type Runtime interface {
@@ -13,8 +16,26 @@ type Runtime interface {
 
 	SignTPMB64(ctx context.Context, msg []byte) (string, error)
 	SignPQB64(ctx context.Context, msg []byte) (string, error)
+	VerifyPQB64(ctx context.Context, msg []byte, sigB64 string) (bool, error)
+	SignPQB64ForLabel(ctx context.Context, label string, msg []byte) (string, error)
+	PQPublicKeyB64ForLabel(ctx context.Context, label string) (string, error)
+	SignHybridB64(ctx context.Context, msg []byte) (tpmSigB64, pqSigB64 string, err error)
+	SupportedSchemes() []string
+	KeyInfo(ctx context.Context) (KeyInfo, error)
+	SignPQB64WithScheme(ctx context.Context, schemeName string, msg []byte) (string, error)
+	PQPublicKeyB64ForScheme(ctx context.Context, schemeName string) (string, error)
+	SignTPMDomainB64(ctx context.Context, domain string, msg []byte) (string, error)
+	SignPQDomainB64(ctx context.Context, domain string, msg []byte) (string, error)
+	SignCanonicalRequest(ctx context.Context, ci requests.CanonicalInput) (*SignedRequest, error)
 
 	EnsurePQKeypair(ctx context.Context) error
+	EnrollmentBundle(ctx context.Context, nonce []byte) (*Bundle, error)
+	HealthCheck(ctx context.Context) error
+	Status(ctx context.Context) (*RuntimeStatus, error)
+	ExportPQKeyFileDEK(ctx context.Context) ([]byte, error)
+	ResealToCurrentTPM(ctx context.Context, priorDEK []byte) error
+	RotatePQKeypair(ctx context.Context) error
+	PreviousPQPublicKeyB64(ctx context.Context) (string, time.Time, error)
 	Close() error
 }
 
@@ -0,0 +1,111 @@
+package cryptoctx
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/quantumauth-io/quantum-go-utils/tpmdevice"
+)
+
+// pinSigner behaves like a TPM key created with tpmdevice.Config.KeyAuth.
+type pinSigner struct {
+	*memSigner
+	pin string
+}
+
+func (p *pinSigner) RequiresAuth() bool { return true }
+
+func (p *pinSigner) Sign(msg []byte) ([]byte, error) { return nil, tpmdevice.ErrKeyAuthRequired }
+
+func (p *pinSigner) SignB64(msg []byte) (string, error) { return "", tpmdevice.ErrKeyAuthRequired }
+
+func (p *pinSigner) SignWithAuth(msg []byte, auth string) ([]byte, error) {
+	if auth != p.pin {
+		return nil, errors.New("wrong PIN")
+	}
+	return p.memSigner.Sign(msg)
+}
+
+func (p *pinSigner) SignWithAuthB64(msg []byte, auth string) (string, error) {
+	if auth != p.pin {
+		return "", errors.New("wrong PIN")
+	}
+	return p.memSigner.SignB64(msg)
+}
+
+func newPINRuntime(t *testing.T, cfg Config) (*runtimeImpl, error) {
+	t.Helper()
+	cfg.PQKeyFilePath = filepath.Join(t.TempDir(), "pqkeys.json.enc")
+	cfg.PQLabel = "test"
+	return newRuntime(context.Background(), cfg, func() (tpmdevice.Client, tpmdevice.Sealer, error) {
+		signer, err := newMemSigner()
+		if err != nil {
+			return nil, nil, err
+		}
+		sealer, err := newMemSealer()
+		if err != nil {
+			return nil, nil, err
+		}
+		return &pinSigner{memSigner: signer, pin: "1234"}, sealer, nil
+	})
+}
+
+func TestNewRejectsKeyAuthWithoutPIN(t *testing.T) {
+	if _, err := newPINRuntime(t, Config{}); !errors.Is(err, ErrTPMKeyAuthMissing) {
+		t.Fatalf("err = %v, want ErrTPMKeyAuthMissing", err)
+	}
+}
+
+func TestTPMKeyAuthCallback(t *testing.T) {
+	var calls int
+	rt, err := newPINRuntime(t, Config{TPMKeyAuth: func(context.Context) (string, error) {
+		calls++
+		return "1234", nil
+	}})
+	if err != nil {
+		t.Fatalf("newRuntime: %v", err)
+	}
+	defer rt.Close()
+
+	if err := rt.HealthCheck(context.Background()); err != nil {
+		t.Fatalf("HealthCheck: %v", err)
+	}
+	if _, _, err := rt.SignHybridB64(context.Background(), []byte("msg")); err != nil {
+		t.Fatalf("SignHybridB64: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("TPMKeyAuth called %d times, want once per TPM signature (2)", calls)
+	}
+}
+
+func TestTPMKeyAuthFallsBackToKeyAuth(t *testing.T) {
+	cfg := Config{}
+	cfg.TPM.KeyAuth = "1234"
+	rt, err := newPINRuntime(t, cfg)
+	if err != nil {
+		t.Fatalf("newRuntime: %v", err)
+	}
+	defer rt.Close()
+
+	sig, err := rt.SignTPMB64(context.Background(), []byte("msg"))
+	if err != nil {
+		t.Fatalf("SignTPMB64: %v", err)
+	}
+	if err := verifyTPMSignatureB64(rt.TPMPublicKeyB64(), []byte("msg"), sig); err != nil {
+		t.Errorf("verify: %v", err)
+	}
+}
+
+func TestTPMKeyAuthWrongPIN(t *testing.T) {
+	rt, err := newPINRuntime(t, Config{TPMKeyAuth: func(context.Context) (string, error) { return "0000", nil }})
+	if err != nil {
+		t.Fatalf("newRuntime: %v", err)
+	}
+	defer rt.Close()
+
+	if err := rt.HealthCheck(context.Background()); err == nil {
+		t.Fatal("HealthCheck with a wrong PIN succeeded")
+	}
+}
@@ -0,0 +1,179 @@
+package cryptoctx
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cloudflare/circl/sign/schemes"
+)
+
+var (
+	// ErrPQSchemeMismatch is returned when a key file was written for a PQ scheme that
+	// is neither PQSchemeName nor one of PQAcceptSchemeNames.
+	ErrPQSchemeMismatch = errors.New("cryptoctx: PQ key file scheme does not match configuration")
+
+	// ErrUnsupportedScheme is returned for a scheme name this runtime doesn't hold a key for.
+	ErrUnsupportedScheme = errors.New("cryptoctx: PQ scheme not supported by this runtime")
+)
+
+// Scheme migration: Config.PQSchemeName is the primary scheme, used by SignPQB64 and
+// for new keys; Config.PQAcceptSchemeNames lists schemes still accepted during a rollout
+// (e.g. ML-DSA-65 while moving to ML-DSA-87). Each accepted scheme's key lives in
+// PQKeyFilePath + "." + lower-case scheme name and is never generated, only kept.
+//
+// When New finds the primary key file written under an accepted scheme, it rewrites
+// that key into the scheme's own file and creates a fresh primary key, so the old key
+// keeps signing for clients that haven't moved yet. Drop the scheme from the list once
+// they have.
+
+func schemeKeyPath(pqPath, schemeName string) string {
+	return pqPath + "." + strings.ToLower(schemeName)
+}
+
+// initAcceptSchemes builds the runtime views for the accepted schemes.
+func (r *runtimeImpl) initAcceptSchemes(names []string) error {
+	for _, name := range names {
+		scheme := schemes.ByName(name)
+		if scheme == nil {
+			return fmt.Errorf("cryptoctx: PQ scheme %q not found", name)
+		}
+		if scheme.Name() == r.scheme.Name() {
+			return fmt.Errorf("cryptoctx: PQ scheme %q is already the primary scheme", name)
+		}
+		if _, dup := r.accept[scheme.Name()]; dup {
+			return fmt.Errorf("cryptoctx: PQ scheme %q listed twice", name)
+		}
+		if r.accept == nil {
+			r.accept = make(map[string]*runtimeImpl, len(names))
+		}
+		r.accept[scheme.Name()] = r.derive(scheme, schemeKeyPath(r.pqPath, scheme.Name()), r.kemKeyPath, r.pqLabel)
+		r.acceptNames = append(r.acceptNames, scheme.Name())
+	}
+	return nil
+}
+
+// migrateScheme moves a primary key file written under an accepted scheme into that
+// scheme's own file. The key is re-encrypted rather than renamed, as the AAD binds the path.
+func (r *runtimeImpl) migrateScheme(ctx context.Context) error {
+	if len(r.accept) == 0 {
+		return nil
+	}
+
+	r.keyMu.Lock()
+	defer r.keyMu.Unlock()
+
+	b, err := os.ReadFile(r.pqPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("cryptoctx: read PQ key file: %w", err)
+	}
+	var env pqEnvelopeV1
+	if err := json.Unmarshal(b, &env); err != nil {
+		return fmt.Errorf("cryptoctx: unmarshal envelope: %w", err)
+	}
+	target, ok := r.accept[env.SigScheme]
+	if !ok {
+		// Primary scheme, a legacy file without the field, or a mismatch readEnvelope reports.
+		return nil
+	}
+
+	old := r.derive(target.scheme, r.pqPath, r.kemKeyPath, r.pqLabel)
+	kp, err := old.decryptPQKeypair(ctx)
+	if err != nil {
+		return err
+	}
+	defer kp.zeroize()
+	if err := old.checkPQKeypair(kp); err != nil {
+		return err
+	}
+
+	target.keyMu.Lock()
+	err = target.writeEncryptedPQKeypair(ctx, *kp, env.keyMeta())
+	target.keyMu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	// A crash before this point just repeats the migration on the next start.
+	if err := os.Remove(r.pqPath); err != nil {
+		return fmt.Errorf("cryptoctx: remove migrated PQ key file: %w", err)
+	}
+	return nil
+}
+
+// checkKeyFileScheme fails New when the primary key file, after migrateScheme, still
+// holds a key for another scheme than PQSchemeName, instead of letting the first sign
+// fail. Files that predate the scheme field are checked by their key sizes.
+func (r *runtimeImpl) checkKeyFileScheme(ctx context.Context) error {
+	r.keyMu.RLock()
+	defer r.keyMu.RUnlock()
+
+	env, _, _, err := r.readEnvelope()
+	if errors.Is(err, ErrMissingPQKeyFile) {
+		return nil
+	}
+	if errors.Is(err, ErrPQSchemeMismatch) {
+		return fmt.Errorf("%w; list the file's scheme in PQAcceptSchemeNames to migrate it", err)
+	}
+	if err != nil || env.SigScheme != "" {
+		return err
+	}
+
+	kp, err := r.decryptPQKeypair(ctx)
+	if err != nil {
+		return err
+	}
+	defer kp.zeroize()
+	if len(kp.Pub) != r.scheme.PublicKeySize() || len(kp.Priv) != r.scheme.PrivateKeySize() {
+		return fmt.Errorf("%w: file key sizes don't fit %s", ErrPQSchemeMismatch, r.scheme.Name())
+	}
+	return nil
+}
+
+// SupportedSchemes returns the PQ schemes this runtime can sign with, primary first.
+func (r *runtimeImpl) SupportedSchemes() []string {
+	if r == nil {
+		return nil
+	}
+	return append([]string{r.scheme.Name()}, r.acceptNames...)
+}
+
+// SignPQB64WithScheme is SignPQB64 with the key for schemeName, which must be the
+// primary scheme or an accepted one.
+func (r *runtimeImpl) SignPQB64WithScheme(ctx context.Context, schemeName string, msg []byte) (string, error) {
+	sr, err := r.forScheme(schemeName)
+	if err != nil {
+		return "", err
+	}
+	return sr.SignPQB64(ctx, msg)
+}
+
+// PQPublicKeyB64ForScheme is PQPublicKeyB64 for the key held for schemeName.
+func (r *runtimeImpl) PQPublicKeyB64ForScheme(ctx context.Context, schemeName string) (string, error) {
+	sr, err := r.forScheme(schemeName)
+	if err != nil {
+		return "", err
+	}
+	return sr.PQPublicKeyB64(ctx)
+}
+
+func (r *runtimeImpl) forScheme(schemeName string) (*runtimeImpl, error) {
+	if r == nil {
+		return nil, fmt.Errorf("cryptoctx: runtime is nil")
+	}
+	if strings.EqualFold(schemeName, r.scheme.Name()) {
+		return r, nil
+	}
+	for name, sr := range r.accept {
+		if strings.EqualFold(schemeName, name) {
+			return sr, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %q", ErrUnsupportedScheme, schemeName)
+}
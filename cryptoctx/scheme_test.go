@@ -0,0 +1,81 @@
+package cryptoctx
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/quantumauth-io/quantum-go-utils/tpmdevice"
+)
+
+// sharedMemOpen returns an open func that hands every runtime the same in-memory signer
+// and sealer, so a second runtime can read the files of the first.
+func sharedMemOpen(t *testing.T) func() (tpmdevice.Client, tpmdevice.Sealer, error) {
+	t.Helper()
+	signer, err := newMemSigner()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sealer, err := newMemSealer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return func() (tpmdevice.Client, tpmdevice.Sealer, error) { return signer, sealer, nil }
+}
+
+func TestNewRejectsKeyFileOfOtherScheme(t *testing.T) {
+	ctx := context.Background()
+	open := sharedMemOpen(t)
+	cfg := Config{PQKeyFilePath: filepath.Join(t.TempDir(), "pqkeys.json.enc"), PQLabel: "test", PQSchemeName: "ML-DSA-65"}
+	if _, err := newRuntime(ctx, cfg, open); err != nil {
+		t.Fatalf("newRuntime(ML-DSA-65): %v", err)
+	}
+
+	cfg.PQSchemeName = "ML-DSA-87"
+	if _, err := newRuntime(ctx, cfg, open); !errors.Is(err, ErrPQSchemeMismatch) {
+		t.Fatalf("newRuntime(ML-DSA-87) err = %v, want ErrPQSchemeMismatch", err)
+	}
+
+	cfg.PQAcceptSchemeNames = []string{"ML-DSA-65"}
+	r, err := newRuntime(ctx, cfg, open)
+	if err != nil {
+		t.Fatalf("newRuntime(ML-DSA-87, accept ML-DSA-65): %v", err)
+	}
+	if _, err := r.SignPQB64WithScheme(ctx, "ML-DSA-65", []byte("msg")); err != nil {
+		t.Fatalf("sign with migrated key: %v", err)
+	}
+}
+
+func TestNewRejectsLegacyKeyFileOfOtherScheme(t *testing.T) {
+	ctx := context.Background()
+	open := sharedMemOpen(t)
+	cfg := Config{PQKeyFilePath: filepath.Join(t.TempDir(), "pqkeys.json.enc"), PQLabel: "test", PQSchemeName: "ML-DSA-65"}
+	if _, err := newRuntime(ctx, cfg, open); err != nil {
+		t.Fatalf("newRuntime(ML-DSA-65): %v", err)
+	}
+
+	// Drop the scheme field, as files written by older versions lack it.
+	b, err := os.ReadFile(cfg.PQKeyFilePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var env map[string]interface{}
+	if err := json.Unmarshal(b, &env); err != nil {
+		t.Fatal(err)
+	}
+	delete(env, "sig_scheme")
+	if b, err = json.Marshal(env); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(cfg.PQKeyFilePath, b, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg.PQSchemeName = "ML-DSA-87"
+	if _, err := newRuntime(ctx, cfg, open); !errors.Is(err, ErrPQSchemeMismatch) {
+		t.Fatalf("newRuntime(ML-DSA-87) err = %v, want ErrPQSchemeMismatch", err)
+	}
+}
@@ -0,0 +1,70 @@
+package cryptoctx
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// RuntimeStatus is a diagnostic snapshot of a Runtime, suitable for a CLI or a status
+// endpoint. Fingerprints are hex SHA-256 of the raw public key bytes.
+type RuntimeStatus struct {
+	TPMPublicKeyFingerprint string
+	PQPublicKeyFingerprint  string // empty unless the key file unseals
+	PQScheme                string
+	KEMScheme               string // empty when no KEM is configured
+	KeyFilePath             string
+	KeyFileExists           bool
+	KeyFileUnseals          bool
+	UnsealError             string // why KeyFileUnseals is false, if the file exists
+	EnvelopeVersion         int    // 0 if the file is missing or unreadable
+}
+
+// Status reports the runtime's keys and key file without signing anything. Unlike
+// HealthCheck it is informational: problems with the key file are reported in the
+// returned status, and an error means the status itself couldn't be produced.
+func (r *runtimeImpl) Status(ctx context.Context) (*RuntimeStatus, error) {
+	if r == nil || r.tpm == nil {
+		return nil, fmt.Errorf("cryptoctx: status: TPM client not initialized")
+	}
+
+	st := &RuntimeStatus{
+		PQScheme:    r.scheme.Name(),
+		KeyFilePath: r.pqPath,
+	}
+	if r.kem != nil {
+		st.KEMScheme = r.kem.Name()
+	}
+
+	st.TPMPublicKeyFingerprint = fingerprint(r.tpm.PublicKey())
+
+	if _, err := os.Stat(r.pqPath); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return st, nil
+		}
+		return nil, fmt.Errorf("cryptoctx: status: stat PQ key file: %w", err)
+	}
+	st.KeyFileExists = true
+
+	if info, err := InspectEnvelope(r.pqPath); err == nil {
+		st.EnvelopeVersion = info.Version
+	}
+
+	kp, err := r.loadPQKeypair(ctx)
+	if err != nil {
+		st.UnsealError = err.Error()
+		return st, nil
+	}
+	defer kp.zeroize()
+	st.KeyFileUnseals = true
+	st.PQPublicKeyFingerprint = fingerprint(kp.Pub)
+	return st, nil
+}
+
+func fingerprint(pub []byte) string {
+	sum := sha256.Sum256(pub)
+	return hex.EncodeToString(sum[:])
+}
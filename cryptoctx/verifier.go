@@ -0,0 +1,119 @@
+package cryptoctx
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"github.com/cloudflare/circl/sign"
+	"github.com/cloudflare/circl/sign/schemes"
+)
+
+// Verifier checks signatures made by a Runtime. It needs no TPM and no key file, so
+// services that only verify clients can use it on any host.
+type Verifier interface {
+	// VerifyTPMB64 verifies a SignTPMB64 signature against a TPMPublicKeyB64 key.
+	VerifyTPMB64(pubB64 string, msg []byte, sigB64 string) error
+	// VerifyPQB64 verifies a SignPQB64 signature against a PQPublicKeyB64 key.
+	VerifyPQB64(pubB64 string, msg []byte, sigB64 string) error
+	// VerifyHybrid requires both the TPM and the PQ signature over msg to verify.
+	VerifyHybrid(tpmPubB64, pqPubB64 string, msg []byte, tpmSigB64, pqSigB64 string) error
+}
+
+type VerifierConfig struct {
+	// CIRCL scheme name of the PQ signatures to verify
+	PQSchemeName string // default: "ML-DSA-65"
+}
+
+type verifierImpl struct {
+	scheme sign.Scheme
+}
+
+// NewVerifier returns a verify-only counterpart to New. Unlike New it touches no
+// hardware or files and only fails on an unknown scheme name.
+func NewVerifier(cfg VerifierConfig) (Verifier, error) {
+	schemeName := cfg.PQSchemeName
+	if schemeName == "" {
+		schemeName = "ML-DSA-65"
+	}
+	scheme := schemes.ByName(schemeName)
+	if scheme == nil {
+		return nil, fmt.Errorf("cryptoctx: PQ scheme %q not found", schemeName)
+	}
+	return &verifierImpl{scheme: scheme}, nil
+}
+
+func (v *verifierImpl) VerifyTPMB64(pubB64 string, msg []byte, sigB64 string) error {
+	if err := verifyTPMSignatureB64(pubB64, msg, sigB64); err != nil {
+		return fmt.Errorf("cryptoctx: %w", err)
+	}
+	return nil
+}
+
+func (v *verifierImpl) VerifyPQB64(pubB64 string, msg []byte, sigB64 string) error {
+	return verifyPQSignatureB64(v.scheme, pubB64, msg, sigB64, nil)
+}
+
+func (v *verifierImpl) VerifyHybrid(tpmPubB64, pqPubB64 string, msg []byte, tpmSigB64, pqSigB64 string) error {
+	if err := v.VerifyTPMB64(tpmPubB64, msg, tpmSigB64); err != nil {
+		return err
+	}
+	return v.VerifyPQB64(pqPubB64, msg, pqSigB64)
+}
+
+// VerifyPQB64 verifies a SignPQB64 signature against the runtime's current PQ public
+// key. A well-formed signature that doesn't match returns (false, nil); an error means
+// the signature couldn't be checked (malformed bytes, unreadable key file).
+func (r *runtimeImpl) VerifyPQB64(ctx context.Context, msg []byte, sigB64 string) (bool, error) {
+	if r == nil {
+		return false, fmt.Errorf("cryptoctx: runtime is nil")
+	}
+	pubB64, err := r.PQPublicKeyB64(ctx)
+	if err != nil {
+		return false, err
+	}
+	return verifyPQ(r.scheme, pubB64, msg, sigB64, nil)
+}
+
+// VerifyPQWithPubB64 verifies a SignPQB64 signature made with the named CIRCL scheme
+// against pubB64, without a Runtime. It reports a mismatch as (false, nil) and
+// malformed input as an error.
+func VerifyPQWithPubB64(schemeName string, pubB64 string, msg []byte, sigB64 string) (bool, error) {
+	scheme := schemes.ByName(schemeName)
+	if scheme == nil {
+		return false, fmt.Errorf("cryptoctx: PQ scheme %q not found", schemeName)
+	}
+	return verifyPQ(scheme, pubB64, msg, sigB64, nil)
+}
+
+// verifyPQSignatureB64 verifies a base64 scheme signature over msg with a base64 public key.
+func verifyPQSignatureB64(scheme sign.Scheme, pubB64 string, msg []byte, sigB64 string, opts *sign.SignatureOpts) error {
+	ok, err := verifyPQ(scheme, pubB64, msg, sigB64, opts)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.New("cryptoctx: PQ signature does not verify")
+	}
+	return nil
+}
+
+func verifyPQ(scheme sign.Scheme, pubB64 string, msg []byte, sigB64 string, opts *sign.SignatureOpts) (bool, error) {
+	pubBytes, err := base64.RawStdEncoding.DecodeString(pubB64)
+	if err != nil {
+		return false, fmt.Errorf("cryptoctx: PQ public key encoding: %w", err)
+	}
+	pk, err := scheme.UnmarshalBinaryPublicKey(pubBytes)
+	if err != nil {
+		return false, fmt.Errorf("cryptoctx: PQ public key: %w", err)
+	}
+	sig, err := base64.RawStdEncoding.DecodeString(sigB64)
+	if err != nil {
+		return false, fmt.Errorf("cryptoctx: PQ signature encoding: %w", err)
+	}
+	if len(sig) != scheme.SignatureSize() {
+		return false, fmt.Errorf("cryptoctx: PQ signature is %d bytes, %s signatures are %d", len(sig), scheme.Name(), scheme.SignatureSize())
+	}
+	return scheme.Verify(pk, msg, sig, opts), nil
+}
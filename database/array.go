@@ -0,0 +1,86 @@
+package database
+
+import (
+	"database/sql/driver"
+	"encoding/binary"
+
+	"github.com/jackc/pgtype"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+)
+
+// pgArray lets one wrapper pass and scan Postgres arrays on both drivers. Arguments are
+// sent as array literals in text format, which both drivers and every array type accept.
+// Scanning uses lib/pq's parser for the text format (database/sql) and pgtype for the
+// binary format pgx asks for; pgx v4 registers the standard array types itself.
+type pgArray struct {
+	v interface{}
+}
+
+// Array wraps a slice argument (Exec(ctx, sql, Array([]string{...}))) or a pointer to a
+// slice scan destination (row.Scan(Array(&dest))) for array columns such as text[] or
+// int8[]. It works the same on AuroraPGXDatabase and CockroachSQLDatabase.
+func Array(v interface{}) interface{} {
+	return &pgArray{v: v}
+}
+
+func (a *pgArray) Value() (driver.Value, error) {
+	return pq.Array(a.v).(driver.Valuer).Value()
+}
+
+func (a *pgArray) Scan(src interface{}) error {
+	scanner, ok := pq.Array(a.v).(interface{ Scan(src interface{}) error })
+	if !ok {
+		return errors.Errorf("cannot scan an array into %T", a.v)
+	}
+	return scanner.Scan(src)
+}
+
+// EncodeText makes pgx send the literal in text format rather than as a binary array.
+func (a *pgArray) EncodeText(ci *pgtype.ConnInfo, buf []byte) ([]byte, error) {
+	v, err := a.Value()
+	if err != nil || v == nil {
+		return nil, err
+	}
+	s, ok := v.(string)
+	if !ok {
+		return nil, errors.Errorf("unexpected array literal type %T", v)
+	}
+	return append(buf, s...), nil
+}
+
+func (a *pgArray) DecodeText(ci *pgtype.ConnInfo, src []byte) error {
+	if src == nil {
+		return a.Scan(nil)
+	}
+	return a.Scan(src)
+}
+
+// DecodeBinary decodes pgx's binary array format using the element type named in the
+// array header, then assigns the elements to the destination slice.
+func (a *pgArray) DecodeBinary(ci *pgtype.ConnInfo, src []byte) error {
+	if src == nil {
+		return a.Scan(nil)
+	}
+	if len(src) < 12 {
+		return errors.Errorf("array header too short: %d bytes", len(src))
+	}
+
+	elemOID := binary.BigEndian.Uint32(src[8:12])
+	dt, ok := ci.DataTypeForOID(elemOID)
+	if !ok {
+		return errors.Errorf("unknown array element type oid %d", elemOID)
+	}
+	elem, ok := dt.Value.(pgtype.ValueTranscoder)
+	if !ok {
+		return errors.Errorf("array element type %s cannot be decoded", dt.Name)
+	}
+
+	arr := pgtype.NewArrayType("_"+dt.Name, elemOID, func() pgtype.ValueTranscoder {
+		return pgtype.NewValue(elem).(pgtype.ValueTranscoder)
+	})
+	if err := arr.DecodeBinary(ci, src); err != nil {
+		return errors.Wrap(err, "failed to decode array")
+	}
+	return arr.AssignTo(a.v)
+}
@@ -20,6 +20,8 @@ import (
 type AuroraPGXDatabase struct {
 	dbPool   *pgxpool.Pool
 	settings DatabaseSettings
+	scope    *shutdownScope
+	churn    *poolChurn
 }
 
 // NewAuroraPGXDatabase creates a NAT/Fargate-friendly pool and verifies connectivity.
@@ -66,6 +68,10 @@ func NewAuroraPGXDatabase(ctx context.Context, dbSettings DatabaseSettings) (Qua
 			// APM instrumentation: do it ONCE on the config
 			apmpgx.Instrument(cfg.ConnConfig)
 
+			churn := &poolChurn{}
+			churn.instrument(cfg)
+			newConnectRamp(dbSettings.ConnectRampUp, int(minPool)).instrument(cfg)
+
 			// If you require TLS and want to be explicit.
 			// Aurora typically works fine with sslmode=require in the DSN,
 			// but this prevents accidental plaintext if your DSN builder is lax.
@@ -90,6 +96,8 @@ func NewAuroraPGXDatabase(ctx context.Context, dbSettings DatabaseSettings) (Qua
 			return []interface{}{&AuroraPGXDatabase{
 				dbPool:   dbPool,
 				settings: dbSettings,
+				scope:    newShutdownScope(),
+				churn:    churn,
 			}}, nil
 		},
 		isRetryableAurora,
@@ -106,6 +114,11 @@ func (db *AuroraPGXDatabase) GetSettings() DatabaseSettings {
 	return db.settings
 }
 
+// Stats reports pool occupancy and connection churn.
+func (db *AuroraPGXDatabase) Stats() PoolStats {
+	return pgxPoolStats(db.dbPool, db.churn)
+}
+
 func (db *AuroraPGXDatabase) MigrateWithIOFS(ctx context.Context, src source.Driver) error {
 	return migrateWithIOFS(ctx, src, db.settings)
 }
@@ -118,44 +131,66 @@ func (db *AuroraPGXDatabase) GetTransaction(ctx context.Context) (QuantumAuthDat
 		AccessMode: pgx.ReadWrite,
 	}
 
+	// The transaction stays in the shutdown scope until Commit/Rollback.
+	ctx, done, err := db.scope.enter(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	retryCfg := retry.DefaultConfig()
 	retryCfg.MaxDelayBeforeRetrying = 1 * time.Second
 	retryCfg.MaxNumRetries = defaultMaxRetry
 
 	result, err := retry.Retry(ctx, retryCfg,
 		func(context.Context) ([]interface{}, error) {
-			txn, err := db.dbPool.BeginTx(ctx, opts)
+			var (
+				txn pgx.Tx
+				err error
+			)
+			if pc := pinnedConn(ctx, db); pc != nil {
+				txn, err = pc.pgx.BeginTx(ctx, opts)
+			} else {
+				txn, err = db.dbPool.BeginTx(ctx, opts)
+			}
 			if err != nil {
-				return nil, errors.Wrap(err, "failed to begin transaction")
+				return nil, errors.Wrap(poolAcquireErr(db.dbPool, err), "failed to begin transaction")
 			}
-			return []interface{}{&pgxTransaction{tx: txn}}, nil
+			return []interface{}{&pgxTransaction{tx: txn, scope: db.scope, done: done}}, nil
 		},
 		isRetryableAurora,
 		"Get DB Transaction (Aurora)",
 	)
 	if err != nil {
+		done()
 		return nil, errors.Wrap(err, "failed to begin transaction after retries")
 	}
 	return result[0].(*pgxTransaction), nil
 }
 
 func (db *AuroraPGXDatabase) Exec(ctx context.Context, sql string, arguments ...interface{}) (QuantumAuthDatabaseExecResult, error) {
+	ctx, done, err := db.scope.enter(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+
 	retryCfg := retry.DefaultConfig()
 	retryCfg.MaxDelayBeforeRetrying = 1 * time.Second
 	retryCfg.MaxNumRetries = defaultMaxRetry
 
 	result, err := retry.Retry(ctx, retryCfg,
 		func(context.Context) ([]interface{}, error) {
-			conn, err := db.dbPool.Acquire(ctx)
+			conn, release, err := db.acquire(ctx)
 			if err != nil {
 				return nil, err
 			}
-			defer conn.Release()
+			defer release()
 
 			cmd, err := conn.Exec(ctx, sql, arguments...)
 			if err != nil {
 				return nil, err
 			}
+			reportExecMetrics(ctx, "exec", sql, cmd.RowsAffected())
 			return []interface{}{&pgxDatabaseExecResult{cmdTag: cmd}}, nil
 		},
 		isRetryableAurora,
@@ -171,38 +206,171 @@ func (db *AuroraPGXDatabase) QueryRow(ctx context.Context, sql string, arguments
 	// QueryRow doesn't execute until Scan, but returning it is fine.
 	// We don’t retry here; the retry would need to wrap Scan which is caller-owned.
 	// If you want retries for QueryRow, do them at repository layer where Scan occurs.
-	conn, err := db.dbPool.Acquire(ctx)
+	ctx, done, err := db.scope.enter(ctx)
 	if err != nil {
 		return nil, err
 	}
+
+	if pc := pinnedConn(ctx, db); pc != nil {
+		return &scopedRow{row: pc.pgx.QueryRow(ctx, sql, arguments...), done: done}, nil
+	}
+
+	conn, err := db.dbPool.Acquire(ctx)
+	if err != nil {
+		done()
+		return nil, poolAcquireErr(db.dbPool, err)
+	}
 	// IMPORTANT: we cannot defer Release here, because QueryRow may be scanned later.
 	// So we must not Acquire a pooled conn for QueryRow.
 	// Instead use pool.QueryRow directly (it manages connection usage internally).
 	conn.Release()
 
-	return db.dbPool.QueryRow(ctx, sql, arguments...), nil
+	return &scopedRow{row: db.dbPool.QueryRow(ctx, sql, arguments...), done: done}, nil
+}
+
+// Exists runs sql, a query returning a single boolean such as
+// SELECT EXISTS(SELECT 1 FROM users WHERE email = $1), and returns that value.
+func (db *AuroraPGXDatabase) Exists(ctx context.Context, sql string, arguments ...interface{}) (bool, error) {
+	var exists bool
+	if err := queryScalar(ctx, db, isRetryableAurora, "Database Exists (Aurora)", &exists, sql, arguments...); err != nil {
+		return false, err
+	}
+	return exists, nil
+}
+
+// Count runs sql, a query returning a single integer such as
+// SELECT count(*) FROM sessions WHERE user_id = $1, and returns that value.
+func (db *AuroraPGXDatabase) Count(ctx context.Context, sql string, arguments ...interface{}) (int64, error) {
+	var n int64
+	if err := queryScalar(ctx, db, isRetryableAurora, "Database Count (Aurora)", &n, sql, arguments...); err != nil {
+		return 0, err
+	}
+	return n, nil
 }
 
 func (db *AuroraPGXDatabase) Query(ctx context.Context, sql string, arguments ...interface{}) (QuantumAuthDatabaseRows, error) {
+	ctx, done, err := db.scope.enter(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	retryCfg := retry.DefaultConfig()
 	retryCfg.MaxDelayBeforeRetrying = 1 * time.Second
 	retryCfg.MaxNumRetries = defaultMaxRetry
 
 	result, err := retry.Retry(ctx, retryCfg,
 		func(context.Context) ([]interface{}, error) {
-			rows, err := db.dbPool.Query(ctx, sql, arguments...)
+			var (
+				rows pgx.Rows
+				err  error
+			)
+			if pc := pinnedConn(ctx, db); pc != nil {
+				rows, err = pc.pgx.Query(ctx, sql, arguments...)
+			} else {
+				rows, err = db.dbPool.Query(ctx, sql, arguments...)
+			}
 			if err != nil {
-				return nil, errors.Wrapf(err, "failed to query %s", sql)
+				return nil, errors.Wrapf(poolAcquireErr(db.dbPool, err), "failed to query %s", sql)
 			}
-			return []interface{}{&pgxDatabaseRows{rows: rows}}, nil
+			return []interface{}{&pgxDatabaseRows{rows: rows, counter: newRowCounter(ctx, sql)}}, nil
 		},
 		isRetryableAurora,
 		"Database Query (Aurora)",
 	)
 	if err != nil {
+		done()
 		return nil, errors.Wrapf(err, "failed to query %s after retries", sql)
 	}
-	return result[0].(*pgxDatabaseRows), nil
+	return &scopedRows{QuantumAuthDatabaseRows: result[0].(*pgxDatabaseRows), done: done}, nil
+}
+
+// ExecScript runs a multi-statement SQL script (e.g. extensions, roles, seed data) one
+// statement at a time in a single transaction. Quotes, dollar-quoted bodies and comments
+// are respected when splitting on semicolons.
+func (db *AuroraPGXDatabase) ExecScript(ctx context.Context, script string) error {
+	return execScript(ctx, db, script)
+}
+
+// DeleteInBatches deletes the rows of table matching whereClause (placeholders bind to
+// arguments) batchSize rows at a time, committing each batch, until none are left. It
+// returns the number of rows deleted, including when it stops early on error or ctx.
+// table and whereClause are interpolated into the statement: never pass user input.
+func (db *AuroraPGXDatabase) DeleteInBatches(ctx context.Context, table string, whereClause string, batchSize int, arguments ...interface{}) (int64, error) {
+	return deleteInBatches(ctx, db, postgresDeleteBatchSQL, table, whereClause, batchSize, arguments...)
+}
+
+// QueryCursor runs sql through a server-side cursor inside a read-only transaction,
+// fetching batchSize rows per round trip instead of buffering the whole result.
+func (db *AuroraPGXDatabase) QueryCursor(ctx context.Context, batchSize int, sql string, arguments ...interface{}) (*Cursor, error) {
+	if batchSize <= 0 {
+		batchSize = defaultCursorBatchSize
+	}
+
+	ctx, done, err := db.scope.enter(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := db.dbPool.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:   pgx.ReadCommitted,
+		AccessMode: pgx.ReadOnly,
+	})
+	if err != nil {
+		done()
+		return nil, errors.Wrap(poolAcquireErr(db.dbPool, err), "failed to begin cursor transaction")
+	}
+
+	name := newCursorName()
+	if _, err := tx.Exec(ctx, declareCursorSQL(name, sql), arguments...); err != nil {
+		_ = tx.Rollback(ctx)
+		done()
+		return nil, errors.Wrapf(err, "failed to declare cursor for %s", sql)
+	}
+
+	return &Cursor{
+		ctx:       ctx,
+		batchSize: batchSize,
+		fetch: func(ctx context.Context) (QuantumAuthDatabaseRows, error) {
+			rows, err := tx.Query(ctx, fetchCursorSQL(name, batchSize))
+			if err != nil {
+				return nil, err
+			}
+			return &pgxDatabaseRows{rows: rows, counter: newRowCounter(ctx, sql)}, nil
+		},
+		finish: func(ctx context.Context) error {
+			defer done()
+			_, closeErr := tx.Exec(ctx, "CLOSE "+name)
+			if err := tx.Rollback(ctx); err != nil {
+				return errors.Wrap(err, "failed to end cursor transaction")
+			}
+			return closeErr
+		},
+	}, nil
+}
+
+// Prepare returns a reusable prepared statement. pgx statements live per connection,
+// so the statement is (idempotently) prepared on whichever pooled conn runs it; this
+// transparently re-prepares after the pool recycles a connection.
+func (db *AuroraPGXDatabase) Prepare(ctx context.Context, name string, sql string) (*PreparedStatement, error) {
+	b := &pgxPreparedBackend{pool: db.dbPool, name: name, sql: sql}
+
+	// Prepare once up front so syntax errors surface here rather than on first use.
+	conn, err := db.dbPool.Acquire(ctx)
+	if err != nil {
+		return nil, errors.Wrap(poolAcquireErr(db.dbPool, err), "failed to acquire connection for prepare")
+	}
+	defer conn.Release()
+	if err := b.prepareOn(ctx, conn); err != nil {
+		return nil, err
+	}
+
+	return &PreparedStatement{
+		name:      name,
+		sql:       sql,
+		backend:   b,
+		retryable: isRetryableAurora,
+		scope:     db.scope,
+	}, nil
 }
 
 func (db *AuroraPGXDatabase) Close() error {
@@ -210,6 +378,47 @@ func (db *AuroraPGXDatabase) Close() error {
 	return nil
 }
 
+// WithDedicatedConn pins one pooled connection to the returned ctx: Exec, Query, QueryRow
+// and GetTransaction called with that ctx (or one derived from it) all run on it, so
+// session state such as temp tables, SET and advisory locks carries across calls.
+// release must be called to return the connection to the pool. Calls on a pinned ctx
+// are not safe to run concurrently, and Rows must be closed before the next call.
+func (db *AuroraPGXDatabase) WithDedicatedConn(ctx context.Context) (context.Context, func(), error) {
+	ctx, done, err := db.scope.enter(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	conn, err := db.dbPool.Acquire(ctx)
+	if err != nil {
+		done()
+		return nil, nil, poolAcquireErr(db.dbPool, err)
+	}
+
+	pinned, release := bindDedicatedConn(ctx, &dedicatedConn{owner: db, pgx: conn}, done)
+	return pinned, release, nil
+}
+
+// acquire returns the ctx's dedicated conn if there is one, otherwise a conn from the pool.
+func (db *AuroraPGXDatabase) acquire(ctx context.Context) (*pgxpool.Conn, func(), error) {
+	if pc := pinnedConn(ctx, db); pc != nil {
+		return pc.pgx, func() {}, nil
+	}
+	conn, err := db.dbPool.Acquire(ctx)
+	if err != nil {
+		return nil, nil, poolAcquireErr(db.dbPool, err)
+	}
+	return conn, conn.Release, nil
+}
+
+// Shutdown cancels every in-flight operation, waits for them to drain (bounded by ctx),
+// then closes the pool. New operations fail with ErrDatabaseShuttingDown.
+func (db *AuroraPGXDatabase) Shutdown(ctx context.Context) error {
+	err := db.scope.shutdown(ctx)
+	db.dbPool.Close()
+	return err
+}
+
 func (db *AuroraPGXDatabase) Ping(ctx context.Context) error {
 	return pingDB(ctx, db.dbPool.Ping)
 }
@@ -217,7 +426,9 @@ func (db *AuroraPGXDatabase) Ping(ctx context.Context) error {
 // --- wrappers to satisfy your interfaces ---
 
 type pgxTransaction struct {
-	tx pgx.Tx
+	tx    pgx.Tx
+	scope *shutdownScope
+	done  func() // leaves the shutdown scope once the tx ends
 }
 
 type pgxDatabaseExecResult struct {
@@ -225,12 +436,23 @@ type pgxDatabaseExecResult struct {
 }
 
 type pgxDatabaseRows struct {
-	rows pgx.Rows
+	rows    pgx.Rows
+	counter rowCounter
+	release func() // set when rows own an acquired pool conn
+}
+
+func (r *pgxDatabaseRows) Close() error {
+	r.rows.Close()
+	r.counter.report()
+	if r.release != nil {
+		r.release()
+		r.release = nil
+	}
+	return nil
 }
 
-func (r *pgxDatabaseRows) Close() error { r.rows.Close(); return nil }
-func (r *pgxDatabaseRows) Err() error   { return r.rows.Err() }
-func (r *pgxDatabaseRows) Next() bool   { return r.rows.Next() }
+func (r *pgxDatabaseRows) Err() error { return r.rows.Err() }
+func (r *pgxDatabaseRows) Next() bool { return r.counter.observe(r.rows.Next()) }
 func (r *pgxDatabaseRows) Scan(dest ...interface{}) error {
 	return r.rows.Scan(dest...)
 }
@@ -241,6 +463,12 @@ func (r *pgxDatabaseExecResult) RowsAffected() (int64, error) {
 
 // Transaction methods
 func (t *pgxTransaction) Exec(ctx context.Context, sql string, arguments ...interface{}) (QuantumAuthDatabaseExecResult, error) {
+	ctx, done, err := t.scope.enter(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+
 	retryCfg := retry.DefaultConfig()
 	retryCfg.MaxDelayBeforeRetrying = 1 * time.Second
 	retryCfg.MaxNumRetries = defaultMaxRetry
@@ -251,6 +479,7 @@ func (t *pgxTransaction) Exec(ctx context.Context, sql string, arguments ...inte
 			if err != nil {
 				return nil, err
 			}
+			reportExecMetrics(ctx, "tx exec", sql, cmd.RowsAffected())
 			return []interface{}{&pgxDatabaseExecResult{cmdTag: cmd}}, nil
 		},
 		isRetryableAurora,
@@ -263,6 +492,8 @@ func (t *pgxTransaction) Exec(ctx context.Context, sql string, arguments ...inte
 }
 
 func (t *pgxTransaction) Commit(ctx context.Context) error {
+	defer t.end()
+
 	retryCfg := retry.DefaultConfig()
 	retryCfg.MaxDelayBeforeRetrying = 1 * time.Second
 	retryCfg.MaxNumRetries = defaultMaxRetry
@@ -284,6 +515,8 @@ func (t *pgxTransaction) Commit(ctx context.Context) error {
 }
 
 func (t *pgxTransaction) Rollback(ctx context.Context) error {
+	defer t.end()
+
 	retryCfg := retry.DefaultConfig()
 	retryCfg.MaxDelayBeforeRetrying = 1 * time.Second
 	retryCfg.MaxNumRetries = defaultMaxRetry
@@ -304,6 +537,88 @@ func (t *pgxTransaction) Rollback(ctx context.Context) error {
 	return nil
 }
 
+func (t *pgxTransaction) end() {
+	if t.done != nil {
+		t.done()
+	}
+}
+
+type pgxPreparedBackend struct {
+	pool *pgxpool.Pool
+	name string
+	sql  string
+}
+
+func (b *pgxPreparedBackend) prepareOn(ctx context.Context, conn *pgxpool.Conn) error {
+	if _, err := conn.Conn().Prepare(ctx, b.name, b.sql); err != nil {
+		return errors.Wrapf(err, "failed to prepare statement %s", b.name)
+	}
+	return nil
+}
+
+func (b *pgxPreparedBackend) acquirePrepared(ctx context.Context) (*pgxpool.Conn, error) {
+	conn, err := b.pool.Acquire(ctx)
+	if err != nil {
+		return nil, poolAcquireErr(b.pool, err)
+	}
+	if err := b.prepareOn(ctx, conn); err != nil {
+		conn.Release()
+		return nil, err
+	}
+	return conn, nil
+}
+
+func (b *pgxPreparedBackend) exec(ctx context.Context, arguments ...interface{}) (QuantumAuthDatabaseExecResult, error) {
+	conn, err := b.acquirePrepared(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Release()
+
+	cmd, err := conn.Exec(ctx, b.name, arguments...)
+	if err != nil {
+		return nil, err
+	}
+	reportExecMetrics(ctx, "prepared exec", b.sql, cmd.RowsAffected())
+	return &pgxDatabaseExecResult{cmdTag: cmd}, nil
+}
+
+func (b *pgxPreparedBackend) query(ctx context.Context, arguments ...interface{}) (QuantumAuthDatabaseRows, error) {
+	conn, err := b.acquirePrepared(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := conn.Query(ctx, b.name, arguments...)
+	if err != nil {
+		conn.Release()
+		return nil, err
+	}
+	return &pgxDatabaseRows{rows: rows, counter: newRowCounter(ctx, b.sql), release: conn.Release}, nil
+}
+
+func (b *pgxPreparedBackend) queryRow(ctx context.Context, arguments ...interface{}) (QuantumAuthDatabaseRow, error) {
+	conn, err := b.acquirePrepared(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &pgxReleasingRow{row: conn.QueryRow(ctx, b.name, arguments...), release: conn.Release}, nil
+}
+
+// close is a no-op: per-connection statements go away as the pool recycles connections.
+func (b *pgxPreparedBackend) close() error { return nil }
+
+// pgxReleasingRow returns its acquired conn to the pool once scanned.
+type pgxReleasingRow struct {
+	row     pgx.Row
+	release func()
+}
+
+func (r *pgxReleasingRow) Scan(dest ...interface{}) error {
+	defer r.release()
+	return r.row.Scan(dest...)
+}
+
 // --- Aurora retry classifier ---
 //
 // NOTE: Retrying writes can duplicate effects if the statement isn't idempotent.
@@ -313,18 +628,23 @@ func isRetryableAurora(err error) bool {
 		return false
 	}
 
+	// Pool saturation is transient; Retry itself gives up once the caller's ctx is done.
+	if errors.Is(err, ErrPoolExhausted) {
+		return true
+	}
+
 	// Network-ish transient errors
+	if retry.IsNetworkError(err) || retry.IsTimeout(err) {
+		return true
+	}
+	// net.Error doesn't always expose Temporary() anymore consistently;
+	// still treat as retryable if it's a net.Error.
 	var netErr net.Error
 	if errors.As(err, &netErr) {
-		// timeout or temporary network errors
-		if netErr.Timeout() {
-			return true
-		}
-		// net.Error doesn't always expose Temporary() anymore consistently;
-		// still treat as retryable if it's a net.Error.
 		return true
 	}
 
+	// Drivers that only report these as text, including a bare "EOF" from a dropped conn.
 	msg := strings.ToLower(err.Error())
 	if strings.Contains(msg, "connection reset by peer") ||
 		strings.Contains(msg, "broken pipe") ||
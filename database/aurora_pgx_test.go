@@ -0,0 +1,52 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"syscall"
+	"testing"
+
+	"github.com/jackc/pgconn"
+)
+
+func TestIsRetryableAurora(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "nil", err: nil},
+		{name: "plain error", err: fmt.Errorf("duplicate key value")},
+		{name: "unique violation", err: &pgconn.PgError{Code: "23505"}},
+
+		// Error strings matched since before the retry package matchers existed.
+		{name: "bare io.EOF", err: io.EOF, want: true},
+		{name: "wrapped EOF", err: fmt.Errorf("query: %w", io.EOF), want: true},
+		{name: "unexpected EOF", err: io.ErrUnexpectedEOF, want: true},
+		{name: "connection reset text", err: fmt.Errorf("read tcp: connection reset by peer"), want: true},
+		{name: "broken pipe text", err: fmt.Errorf("write tcp: broken pipe"), want: true},
+		{name: "connection refused text", err: fmt.Errorf("dial tcp: connection refused"), want: true},
+		{name: "server closed text", err: fmt.Errorf("server closed the connection unexpectedly"), want: true},
+		{name: "i/o timeout text", err: fmt.Errorf("read tcp 10.0.0.1:5432: i/o timeout"), want: true},
+		{name: "timeout text", err: fmt.Errorf("canceling statement due to statement timeout"), want: true},
+
+		{name: "net.Error", err: &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}, want: true},
+		{name: "DNS not found", err: &net.DNSError{Err: "no such host", Name: "db.invalid", IsNotFound: true}, want: true},
+		{name: "pool exhausted", err: fmt.Errorf("%w: %w", ErrPoolExhausted, context.DeadlineExceeded), want: true},
+
+		{name: "serialization failure", err: &pgconn.PgError{Code: "40001"}, want: true},
+		{name: "deadlock", err: &pgconn.PgError{Code: "40P01"}, want: true},
+		{name: "admin shutdown", err: &pgconn.PgError{Code: "57P01"}, want: true},
+		{name: "too many connections", err: &pgconn.PgError{Code: "53300"}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isRetryableAurora(tt.err); got != tt.want {
+				t.Errorf("isRetryableAurora(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+const defaultDeleteBatchSize = 1000
+
+// deleteBatchesSQL is run once per batch. Each Exec runs in its own implicit transaction,
+// so every batch commits (and releases its locks) before the next one starts.
+type deleteBatchesSQL func(table string, whereClause string, batchSize int) string
+
+// Postgres has no DELETE ... LIMIT, so pick the batch by ctid.
+func postgresDeleteBatchSQL(table string, whereClause string, batchSize int) string {
+	return fmt.Sprintf("DELETE FROM %s WHERE ctid IN (SELECT ctid FROM %s WHERE %s LIMIT %d)",
+		table, table, whereClause, batchSize)
+}
+
+// CockroachDB has no ctid but supports DELETE ... LIMIT.
+func cockroachDeleteBatchSQL(table string, whereClause string, batchSize int) string {
+	return fmt.Sprintf("DELETE FROM %s WHERE %s LIMIT %d", table, whereClause, batchSize)
+}
+
+func deleteInBatches(ctx context.Context, db QuantumAuthDatabase, build deleteBatchesSQL, table string, whereClause string, batchSize int, arguments ...interface{}) (int64, error) {
+	if batchSize <= 0 {
+		batchSize = defaultDeleteBatchSize
+	}
+	if whereClause == "" {
+		whereClause = "TRUE"
+	}
+	sql := build(table, whereClause, batchSize)
+
+	var total int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return total, errors.Wrapf(err, "batched delete from %s stopped after %d rows", table, total)
+		}
+
+		res, err := db.Exec(ctx, sql, arguments...)
+		if err != nil {
+			return total, errors.Wrapf(err, "batched delete from %s failed after %d rows", table, total)
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return total, errors.Wrapf(err, "batched delete from %s failed after %d rows", table, total)
+		}
+		total += n
+		if n < int64(batchSize) {
+			return total, nil
+		}
+	}
+}
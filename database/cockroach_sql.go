@@ -3,7 +3,6 @@ package database
 import (
 	"context"
 	"database/sql"
-	"fmt"
 	"time"
 
 	_ "github.com/golang-migrate/migrate/v4/database/cockroachdb"
@@ -19,18 +18,28 @@ import (
 type CockroachSQLDatabase struct {
 	dbPool   *sql.DB
 	settings DatabaseSettings
+	scope    *shutdownScope
+	ramp     *connectRamp // paces WarmUp; database/sql opens other connections on demand
 }
 
 func (db *CockroachSQLDatabase) MigrateWithIOFS(ctx context.Context, source source.Driver) error {
 	return migrateWithIOFS(ctx, source, db.settings)
 }
 
+// Stats reports pool occupancy; see PoolStats for what database/sql can't count.
+func (db *CockroachSQLDatabase) Stats() PoolStats {
+	return sqlPoolStats(db.dbPool)
+}
+
 type sqlDatabaseRows struct {
-	rows *sql.Rows
+	rows    *sql.Rows
+	counter rowCounter
 }
 
 type sqlTransaction struct {
-	tx *sql.Tx
+	tx    *sql.Tx
+	scope *shutdownScope
+	done  func() // leaves the shutdown scope once the tx ends
 }
 
 type sqlDatabaseExecResult struct {
@@ -49,13 +58,18 @@ func NewCockroachSQLDatabase(ctx context.Context, dbSettings DatabaseSettings) (
 	}
 	result, err := retry.Retry(ctx, retryCfg,
 		func(context.Context) ([]interface{}, error) {
-			db, err3 := apmsql.Open("postgres", fmt.Sprintf(connStr))
+			db, err3 := apmsql.Open("postgres", connStr)
 			if err3 != nil {
 				return nil, errors.Wrap(err3, "error opening the database")
 			}
 
 			dbPoolWithConfig := setDBConfig(db, dbSettings)
-			return []interface{}{&CockroachSQLDatabase{dbPool: dbPoolWithConfig.(*sql.DB), settings: dbSettings}}, nil
+			return []interface{}{&CockroachSQLDatabase{
+				dbPool:   dbPoolWithConfig.(*sql.DB),
+				settings: dbSettings,
+				scope:    newShutdownScope(),
+				ramp:     newConnectRamp(dbSettings.ConnectRampUp, warmUpTarget(dbSettings, 0)),
+			}}, nil
 
 		},
 		nil,
@@ -69,25 +83,182 @@ func NewCockroachSQLDatabase(ctx context.Context, dbSettings DatabaseSettings) (
 }
 
 func (db *CockroachSQLDatabase) QueryRow(ctx context.Context, sql string, arguments ...interface{}) (QuantumAuthDatabaseRow, error) {
-	return db.dbPool.QueryRowContext(ctx, sql, arguments...), nil
+	ctx, done, err := db.scope.enter(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &scopedRow{row: db.conn(ctx).QueryRowContext(ctx, sql, arguments...), done: done}, nil
+}
+
+// Exists runs sql, a query returning a single boolean; see AuroraPGXDatabase.Exists.
+func (db *CockroachSQLDatabase) Exists(ctx context.Context, sql string, arguments ...interface{}) (bool, error) {
+	var exists bool
+	if err := queryScalar(ctx, db, isRetryable, "Database Exists", &exists, sql, arguments...); err != nil {
+		return false, err
+	}
+	return exists, nil
+}
+
+// Count runs sql, a query returning a single integer; see AuroraPGXDatabase.Count.
+func (db *CockroachSQLDatabase) Count(ctx context.Context, sql string, arguments ...interface{}) (int64, error) {
+	var n int64
+	if err := queryScalar(ctx, db, isRetryable, "Database Count", &n, sql, arguments...); err != nil {
+		return 0, err
+	}
+	return n, nil
 }
 
 func (db *CockroachSQLDatabase) Query(ctx context.Context, sql string, arguments ...interface{}) (QuantumAuthDatabaseRows, error) {
-	result, err := db.dbPool.QueryContext(ctx, sql, arguments...)
+	ctx, done, err := db.scope.enter(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result, err := db.conn(ctx).QueryContext(ctx, sql, arguments...)
 	if err != nil {
+		done()
 		return nil, err
 	}
-	return &sqlDatabaseRows{result}, nil
+	return &scopedRows{QuantumAuthDatabaseRows: &sqlDatabaseRows{rows: result, counter: newRowCounter(ctx, sql)}, done: done}, nil
+}
+
+// ExecScript runs script statement by statement in one transaction; see AuroraPGXDatabase.ExecScript.
+func (db *CockroachSQLDatabase) ExecScript(ctx context.Context, script string) error {
+	return execScript(ctx, db, script)
 }
+
+// DeleteInBatches repeats DELETE ... LIMIT batchSize until no rows match whereClause,
+// one transaction per batch; see AuroraPGXDatabase.DeleteInBatches.
+func (db *CockroachSQLDatabase) DeleteInBatches(ctx context.Context, table string, whereClause string, batchSize int, arguments ...interface{}) (int64, error) {
+	return deleteInBatches(ctx, db, cockroachDeleteBatchSQL, table, whereClause, batchSize, arguments...)
+}
+
+// QueryCursor runs sql through a server-side cursor inside a read-only transaction,
+// fetching batchSize rows per round trip instead of buffering the whole result.
+func (db *CockroachSQLDatabase) QueryCursor(ctx context.Context, batchSize int, sql string, arguments ...interface{}) (*Cursor, error) {
+	if batchSize <= 0 {
+		batchSize = defaultCursorBatchSize
+	}
+
+	ctx, done, err := db.scope.enter(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := db.conn(ctx).BeginTx(ctx, readOnlyTxOptions())
+	if err != nil {
+		done()
+		return nil, errors.Wrap(err, "failed to begin cursor transaction")
+	}
+
+	name := newCursorName()
+	if _, err := tx.ExecContext(ctx, declareCursorSQL(name, sql), arguments...); err != nil {
+		_ = tx.Rollback()
+		done()
+		return nil, errors.Wrapf(err, "failed to declare cursor for %s", sql)
+	}
+
+	return &Cursor{
+		ctx:       ctx,
+		batchSize: batchSize,
+		fetch: func(ctx context.Context) (QuantumAuthDatabaseRows, error) {
+			rows, err := tx.QueryContext(ctx, fetchCursorSQL(name, batchSize))
+			if err != nil {
+				return nil, err
+			}
+			return &sqlDatabaseRows{rows: rows, counter: newRowCounter(ctx, sql)}, nil
+		},
+		finish: func(ctx context.Context) error {
+			defer done()
+			_, closeErr := tx.ExecContext(ctx, "CLOSE "+name)
+			if err := tx.Rollback(); err != nil {
+				return errors.Wrap(err, "failed to end cursor transaction")
+			}
+			return closeErr
+		},
+	}, nil
+}
+
+// Prepare returns a reusable prepared statement. database/sql re-prepares the
+// statement on new connections automatically, so recycled conns are handled for us.
+// On a ctx from WithDedicatedConn the statement is bound to that conn and stops working
+// once it is released.
+func (db *CockroachSQLDatabase) Prepare(ctx context.Context, name string, sql string) (*PreparedStatement, error) {
+	ctx, done, err := db.scope.enter(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+
+	stmt, err := db.conn(ctx).PrepareContext(ctx, sql)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to prepare statement %s", name)
+	}
+	return &PreparedStatement{
+		name:      name,
+		sql:       sql,
+		backend:   &sqlPreparedBackend{stmt: stmt, sql: sql},
+		retryable: isRetryable,
+		scope:     db.scope,
+	}, nil
+}
+
 func (db *CockroachSQLDatabase) Close() error {
 	return db.dbPool.Close()
 }
 
+// WithDedicatedConn pins one pooled connection to the returned ctx: Exec, Query, QueryRow
+// and GetTransaction called with that ctx (or one derived from it) all run on it, so
+// session state such as temp tables, SET and advisory locks carries across calls.
+// release must be called to return the connection to the pool.
+func (db *CockroachSQLDatabase) WithDedicatedConn(ctx context.Context) (context.Context, func(), error) {
+	ctx, done, err := db.scope.enter(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	conn, err := db.dbPool.Conn(ctx)
+	if err != nil {
+		done()
+		return nil, nil, errors.Wrap(err, "failed to acquire dedicated connection")
+	}
+
+	pinned, release := bindDedicatedConn(ctx, &dedicatedConn{owner: db, sql: conn}, done)
+	return pinned, release, nil
+}
+
+// sqlConn is what *sql.DB and a dedicated *sql.Conn have in common.
+type sqlConn interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+}
+
+// conn returns the ctx's dedicated conn if there is one, otherwise the pool.
+func (db *CockroachSQLDatabase) conn(ctx context.Context) sqlConn {
+	if pc := pinnedConn(ctx, db); pc != nil {
+		return pc.sql
+	}
+	return db.dbPool
+}
+
+// Shutdown cancels every in-flight operation, waits for them to drain (bounded by ctx),
+// then closes the pool. New operations fail with ErrDatabaseShuttingDown.
+func (db *CockroachSQLDatabase) Shutdown(ctx context.Context) error {
+	err := db.scope.shutdown(ctx)
+	if closeErr := db.dbPool.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
 func (db *CockroachSQLDatabase) Ping(ctx context.Context) error {
 	return pingDB(ctx, db.dbPool.PingContext)
 }
 
 func (dbRows *sqlDatabaseRows) Close() error {
+	defer dbRows.counter.report()
 	return dbRows.rows.Close()
 }
 
@@ -96,11 +267,22 @@ func (dbRows *sqlDatabaseRows) Err() error {
 }
 
 func (dbRows *sqlDatabaseRows) Next() bool {
-	return dbRows.rows.Next()
+	return dbRows.counter.observe(dbRows.rows.Next())
 }
 
 func (db *CockroachSQLDatabase) Exec(ctx context.Context, sql string, arguments ...interface{}) (QuantumAuthDatabaseExecResult, error) {
-	return db.dbPool.ExecContext(ctx, sql, arguments...)
+	ctx, done, err := db.scope.enter(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+
+	result, err := db.conn(ctx).ExecContext(ctx, sql, arguments...)
+	if err != nil {
+		return nil, err
+	}
+	reportSQLResultMetrics(ctx, "exec", sql, result)
+	return result, nil
 }
 
 func (db *CockroachSQLDatabase) GetTransaction(ctx context.Context) (QuantumAuthDatabaseTransaction, error) {
@@ -109,11 +291,18 @@ func (db *CockroachSQLDatabase) GetTransaction(ctx context.Context) (QuantumAuth
 		Isolation: sql.LevelDefault,
 	}
 
-	txResult, err := db.dbPool.BeginTx(ctx, opts)
+	// database/sql rolls the tx back if this ctx is cancelled, so Shutdown aborts open transactions.
+	ctx, done, err := db.scope.enter(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	txResult, err := db.conn(ctx).BeginTx(ctx, opts)
 	if err != nil {
+		done()
 		return nil, err
 	}
-	return &sqlTransaction{txResult}, nil
+	return &sqlTransaction{tx: txResult, scope: db.scope, done: done}, nil
 }
 
 func (dbRows *sqlDatabaseRows) Scan(dest ...interface{}) error {
@@ -125,11 +314,71 @@ func (sqlResult sqlDatabaseExecResult) RowsAffected() (int64, error) {
 }
 
 func (sqlTx *sqlTransaction) Exec(ctx context.Context, sql string, arguments ...interface{}) (QuantumAuthDatabaseExecResult, error) {
-	return sqlTx.tx.ExecContext(ctx, sql, arguments...)
+	ctx, done, err := sqlTx.scope.enter(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+
+	result, err := sqlTx.tx.ExecContext(ctx, sql, arguments...)
+	if err != nil {
+		return nil, err
+	}
+	reportSQLResultMetrics(ctx, "tx exec", sql, result)
+	return result, nil
+}
+
+type sqlPreparedBackend struct {
+	stmt *sql.Stmt
+	sql  string
+}
+
+func (b *sqlPreparedBackend) exec(ctx context.Context, arguments ...interface{}) (QuantumAuthDatabaseExecResult, error) {
+	result, err := b.stmt.ExecContext(ctx, arguments...)
+	if err != nil {
+		return nil, err
+	}
+	reportSQLResultMetrics(ctx, "prepared exec", b.sql, result)
+	return result, nil
+}
+
+func (b *sqlPreparedBackend) query(ctx context.Context, arguments ...interface{}) (QuantumAuthDatabaseRows, error) {
+	rows, err := b.stmt.QueryContext(ctx, arguments...)
+	if err != nil {
+		return nil, err
+	}
+	return &sqlDatabaseRows{rows: rows, counter: newRowCounter(ctx, b.sql)}, nil
+}
+
+func (b *sqlPreparedBackend) queryRow(ctx context.Context, arguments ...interface{}) (QuantumAuthDatabaseRow, error) {
+	return b.stmt.QueryRowContext(ctx, arguments...), nil
+}
+
+func (b *sqlPreparedBackend) close() error { return b.stmt.Close() }
+
+func readOnlyTxOptions() *sql.TxOptions {
+	return &sql.TxOptions{ReadOnly: true}
+}
+
+// reportSQLResultMetrics skips drivers that can't report rows affected.
+func reportSQLResultMetrics(ctx context.Context, operation string, sql string, result sql.Result) {
+	n, err := result.RowsAffected()
+	if err != nil {
+		return
+	}
+	reportExecMetrics(ctx, operation, sql, n)
 }
 func (sqlTx *sqlTransaction) Commit(ctx context.Context) error {
+	defer sqlTx.end()
 	return sqlTx.tx.Commit()
 }
 func (sqlTx *sqlTransaction) Rollback(ctx context.Context) error {
+	defer sqlTx.end()
 	return sqlTx.tx.Rollback()
 }
+
+func (sqlTx *sqlTransaction) end() {
+	if sqlTx.done != nil {
+		sqlTx.done()
+	}
+}
@@ -0,0 +1,104 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+const defaultCursorBatchSize = 1000
+
+// Cursor streams a large result set through a server-side cursor, fetching batchSize rows at a time.
+// It holds a transaction open until Close, so always Close it.
+type Cursor struct {
+	ctx       context.Context
+	batchSize int
+
+	fetch  func(ctx context.Context) (QuantumAuthDatabaseRows, error)
+	finish func(ctx context.Context) error
+
+	batch      QuantumAuthDatabaseRows
+	batchCount int
+	done       bool
+	closed     bool
+	err        error
+}
+
+func newCursorName() string {
+	return "qa_cursor_" + strings.ReplaceAll(uuid.NewString(), "-", "")
+}
+
+func declareCursorSQL(name string, sql string) string {
+	return fmt.Sprintf("DECLARE %s NO SCROLL CURSOR FOR %s", name, sql)
+}
+
+func fetchCursorSQL(name string, batchSize int) string {
+	return fmt.Sprintf("FETCH FORWARD %d FROM %s", batchSize, name)
+}
+
+// Next scans the next row into dest, fetching a new batch when the current one is exhausted.
+// It returns false at the end of the result set or on error; check Err afterwards.
+func (c *Cursor) Next(dest ...interface{}) bool {
+	if c == nil || c.closed || c.err != nil {
+		return false
+	}
+
+	for {
+		if c.batch == nil {
+			if c.done {
+				return false
+			}
+			rows, err := c.fetch(c.ctx)
+			if err != nil {
+				c.err = errors.Wrap(err, "failed to fetch cursor batch")
+				return false
+			}
+			c.batch = rows
+			c.batchCount = 0
+		}
+
+		if c.batch.Next() {
+			c.batchCount++
+			if err := c.batch.Scan(dest...); err != nil {
+				c.err = errors.Wrap(err, "failed to scan cursor row")
+				return false
+			}
+			return true
+		}
+
+		err := c.batch.Err()
+		_ = c.batch.Close()
+		c.batch = nil
+		if err != nil {
+			c.err = errors.Wrap(err, "failed to read cursor batch")
+			return false
+		}
+		// A short batch means the cursor is drained; skip the extra empty FETCH.
+		if c.batchCount < c.batchSize {
+			c.done = true
+		}
+	}
+}
+
+func (c *Cursor) Err() error {
+	if c == nil {
+		return nil
+	}
+	return c.err
+}
+
+// Close closes the server-side cursor and ends its transaction. Safe to call more than once.
+func (c *Cursor) Close() error {
+	if c == nil || c.closed {
+		return nil
+	}
+	c.closed = true
+	if c.batch != nil {
+		_ = c.batch.Close()
+		c.batch = nil
+	}
+	return c.finish(c.ctx)
+}
@@ -0,0 +1,44 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type dedicatedConnKey struct{}
+
+// dedicatedConn is a pool connection pinned to a ctx by WithDedicatedConn.
+// Exactly one of pgx/sql is set, matching the database that created it.
+type dedicatedConn struct {
+	owner interface{} // the database that pinned it; other databases ignore it
+	pgx   *pgxpool.Conn
+	sql   *sql.Conn
+}
+
+// pinnedConn returns the connection WithDedicatedConn bound to ctx for owner, or nil.
+func pinnedConn(ctx context.Context, owner interface{}) *dedicatedConn {
+	c, _ := ctx.Value(dedicatedConnKey{}).(*dedicatedConn)
+	if c == nil || c.owner != owner {
+		return nil
+	}
+	return c
+}
+
+// bindDedicatedConn stores c on ctx and returns a release func that is safe to call more than once.
+func bindDedicatedConn(ctx context.Context, c *dedicatedConn, done func()) (context.Context, func()) {
+	var once sync.Once
+	return context.WithValue(ctx, dedicatedConnKey{}, c), func() {
+		once.Do(func() {
+			if c.pgx != nil {
+				c.pgx.Release()
+			}
+			if c.sql != nil {
+				_ = c.sql.Close()
+			}
+			done()
+		})
+	}
+}
@@ -50,6 +50,11 @@ type DatabaseSettings struct {
 	MaxPoolSize           uint // pgx
 	MinPoolSize           uint // pgx
 	PoolSize              uint // sql
+	// Spread the first MinPoolSize connections over this long (0 = all at once)
+	ConnectRampUp time.Duration
+	// When a replica died mid-migration, force its dirty version back and re-apply the
+	// migration instead of failing until someone runs migrate force by hand
+	MigrationForceDirty bool
 }
 
 func migrateWithIOFS(ctx context.Context, source source.Driver, cfg DatabaseSettings) error {
@@ -62,12 +67,28 @@ func migrateWithIOFS(ctx context.Context, source source.Driver, cfg DatabaseSett
 		return errors.Wrap(err, "Failed to create connection string")
 	}
 
+	// Only one replica migrates at a time; the others wait here and then find nothing to do.
+	lock, err := acquireMigrationLock(ctx, connectionString)
+	if err != nil {
+		return err
+	}
+	defer lock.release()
+
+	// Only a dirty state found on taking over from a dead holder is theirs; after our own
+	// first attempt it may be ours, and re-applying a half-applied migration isn't safe.
+	forceDirty := cfg.MigrationForceDirty && lock.recovered
 	_, err = retry.Retry(ctx, retryCfg,
 		func(context.Context) ([]interface{}, error) {
 			m, err2 := migrate.NewWithSourceInstance("iofs", source, connectionString)
 			if err2 != nil {
 				return nil, errors.Wrap(err2, "Failed to initialize migrations")
 			}
+			if forceDirty {
+				if err4 := clearDirtyMigration(m, source); err4 != nil {
+					return nil, err4
+				}
+				forceDirty = false
+			}
 			if err3 := m.Up(); err3 != nil && err3.Error() != "no change" {
 				return nil, errors.Wrap(err3, "error migrating database schema")
 			}
@@ -204,8 +225,7 @@ func isRetryable(err error) bool {
 	}
 
 	// 3) network-level errors (e.g. "use of closed network connection")
-	var netErr *net.OpError
-	if errors.As(err, &netErr) {
+	if retry.IsNetworkError(err) {
 		// let the pool create a fresh connection and retry
 		return true
 	}
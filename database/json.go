@@ -0,0 +1,119 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"reflect"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// JSON/JSONB helpers that work the same on both drivers. pgx v4 already knows the json
+// and jsonb types; the helpers go through driver.Valuer / sql.Scanner, which both
+// drivers honour, so no codec registration is needed.
+
+type jsonValue struct {
+	v interface{}
+}
+
+// JSONValue wraps v as a query argument that is sent as its JSON encoding. A nil v
+// (including a nil pointer, map or slice) is sent as SQL NULL, not as JSON null.
+func JSONValue(v interface{}) driver.Valuer {
+	return jsonValue{v: v}
+}
+
+func (j jsonValue) Value() (driver.Value, error) {
+	if isNilValue(j.v) {
+		return nil, nil
+	}
+	b, err := json.Marshal(j.v)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal JSON argument")
+	}
+	// A string is sent as text on both drivers, which Postgres casts to json/jsonb.
+	return string(b), nil
+}
+
+type jsonScanner struct {
+	dest interface{}
+}
+
+// ScanJSON wraps dest (a pointer) so a json/jsonb column scans straight into it. SQL NULL
+// is decoded as JSON null: pointers, maps, slices and interfaces become nil, and other
+// types are left unchanged.
+func ScanJSON(dest interface{}) interface{ Scan(src interface{}) error } {
+	return &jsonScanner{dest: dest}
+}
+
+func (s *jsonScanner) Scan(src interface{}) error {
+	var b []byte
+	switch v := src.(type) {
+	case nil:
+		b = []byte("null")
+	case []byte:
+		b = v
+	case string:
+		b = []byte(v)
+	default:
+		return errors.Errorf("cannot scan %T into JSON", src)
+	}
+	// jsonb in binary format starts with a version byte; JSON text never starts with 0x01.
+	if len(b) > 0 && b[0] == 1 {
+		b = b[1:]
+	}
+	if err := json.Unmarshal(b, s.dest); err != nil {
+		return errors.Wrap(err, "failed to unmarshal JSON column")
+	}
+	return nil
+}
+
+// QueryJSON runs a query returning one json/jsonb column and decodes the first row into
+// dest. It returns ErrNoRows when there is no row.
+func QueryJSON(ctx context.Context, db QuantumAuthDatabase, dest interface{}, sql string, arguments ...interface{}) error {
+	row, err := db.QueryRow(ctx, sql, arguments...)
+	if err != nil {
+		return err
+	}
+	if err := row.Scan(ScanJSON(dest)); err != nil {
+		return ConditionallyConvertToErrNoRows(err)
+	}
+	return nil
+}
+
+// ExecJSON is Exec with every struct or map argument (other than time.Time and values
+// that already implement driver.Valuer) wrapped in JSONValue.
+func ExecJSON(ctx context.Context, db QuantumAuthDatabase, sql string, arguments ...interface{}) (QuantumAuthDatabaseExecResult, error) {
+	args := make([]interface{}, len(arguments))
+	for i, arg := range arguments {
+		args[i] = jsonArg(arg)
+	}
+	return db.Exec(ctx, sql, args...)
+}
+
+func jsonArg(arg interface{}) interface{} {
+	switch arg.(type) {
+	case nil, driver.Valuer, time.Time, *time.Time:
+		return arg
+	}
+	t := reflect.TypeOf(arg)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.Struct || t.Kind() == reflect.Map {
+		return JSONValue(arg)
+	}
+	return arg
+}
+
+func isNilValue(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	switch rv := reflect.ValueOf(v); rv.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface:
+		return rv.IsNil()
+	}
+	return false
+}
@@ -0,0 +1,105 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+const defaultKVTable = "kv"
+
+// KVStoreTableSQL returns the CREATE TABLE statement for a KVStore table, for services
+// that manage the table through their own migrations rather than KVStore.EnsureTable.
+func KVStoreTableSQL(table string) string {
+	if table == "" {
+		table = defaultKVTable
+	}
+	return fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	key        TEXT PRIMARY KEY,
+	value      BYTEA NOT NULL,
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+)`, table)
+}
+
+// KVStore is a small durable key-value store in a single table. Every call goes through
+// the underlying QuantumAuthDatabase, so it gets the same retries as any other query.
+type KVStore struct {
+	db    QuantumAuthDatabase
+	table string
+}
+
+// NewKVStore returns a KVStore over table (default "kv"). The table name is interpolated
+// into queries, so it must be a trusted identifier.
+func NewKVStore(db QuantumAuthDatabase, table string) *KVStore {
+	if table == "" {
+		table = defaultKVTable
+	}
+	return &KVStore{db: db, table: table}
+}
+
+// EnsureTable creates the table if it doesn't exist.
+func (s *KVStore) EnsureTable(ctx context.Context) error {
+	if _, err := s.db.Exec(ctx, KVStoreTableSQL(s.table)); err != nil {
+		return errors.Wrapf(err, "failed to create kv table %s", s.table)
+	}
+	return nil
+}
+
+// Get returns the value for key, or ErrNoRows if it isn't set.
+func (s *KVStore) Get(ctx context.Context, key string) ([]byte, error) {
+	row, err := s.db.QueryRow(ctx, `SELECT value FROM `+s.table+` WHERE key = $1`, key)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get kv key %s", key)
+	}
+	var value []byte
+	if err := row.Scan(&value); err != nil {
+		return nil, ConditionallyConvertToErrNoRows(err)
+	}
+	return value, nil
+}
+
+// Set stores value under key, replacing any existing value.
+func (s *KVStore) Set(ctx context.Context, key string, value []byte) error {
+	if _, err := s.db.Exec(ctx,
+		`INSERT INTO `+s.table+` (key, value, updated_at) VALUES ($1, $2, now())
+		ON CONFLICT (key) DO UPDATE SET value = excluded.value, updated_at = excluded.updated_at`,
+		key, value); err != nil {
+		return errors.Wrapf(err, "failed to set kv key %s", key)
+	}
+	return nil
+}
+
+// Delete removes key. Deleting a missing key is not an error.
+func (s *KVStore) Delete(ctx context.Context, key string) error {
+	if _, err := s.db.Exec(ctx, `DELETE FROM `+s.table+` WHERE key = $1`, key); err != nil {
+		return errors.Wrapf(err, "failed to delete kv key %s", key)
+	}
+	return nil
+}
+
+// CompareAndSwap sets key to newValue only if its current value is oldValue, and reports
+// whether it did. A nil oldValue means "only if key is not set".
+func (s *KVStore) CompareAndSwap(ctx context.Context, key string, oldValue, newValue []byte) (bool, error) {
+	var (
+		res QuantumAuthDatabaseExecResult
+		err error
+	)
+	if oldValue == nil {
+		res, err = s.db.Exec(ctx,
+			`INSERT INTO `+s.table+` (key, value, updated_at) VALUES ($1, $2, now()) ON CONFLICT (key) DO NOTHING`,
+			key, newValue)
+	} else {
+		res, err = s.db.Exec(ctx,
+			`UPDATE `+s.table+` SET value = $3, updated_at = now() WHERE key = $1 AND value = $2`,
+			key, oldValue, newValue)
+	}
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to compare-and-swap kv key %s", key)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to compare-and-swap kv key %s", key)
+	}
+	return n == 1, nil
+}
@@ -0,0 +1,84 @@
+package database
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/quantumauth-io/quantum-go-utils/log"
+)
+
+// RowMetrics describes how much data a single statement moved.
+// RowsAffected is -1 for queries, RowsReturned is -1 for execs.
+type RowMetrics struct {
+	Operation    string
+	SQL          string
+	RowsAffected int64
+	RowsReturned int64
+}
+
+type RowMetricsHook func(ctx context.Context, m RowMetrics)
+
+var rowMetricsHook atomic.Pointer[RowMetricsHook]
+
+// SetRowMetricsHook registers a callback invoked after every Exec and after a Query's rows are closed.
+// Pass nil to remove it. Metrics are also logged at debug level regardless of the hook.
+func SetRowMetricsHook(hook RowMetricsHook) {
+	if hook == nil {
+		rowMetricsHook.Store(nil)
+		return
+	}
+	rowMetricsHook.Store(&hook)
+}
+
+func reportRowMetrics(ctx context.Context, m RowMetrics) {
+	log.Debug("Database row metrics",
+		"operation", m.Operation,
+		"rowsAffected", m.RowsAffected,
+		"rowsReturned", m.RowsReturned,
+		"sql", m.SQL,
+	)
+	if hook := rowMetricsHook.Load(); hook != nil {
+		(*hook)(ctx, m)
+	}
+}
+
+func reportExecMetrics(ctx context.Context, operation string, sql string, rowsAffected int64) {
+	reportRowMetrics(ctx, RowMetrics{
+		Operation:    operation,
+		SQL:          sql,
+		RowsAffected: rowsAffected,
+		RowsReturned: -1,
+	})
+}
+
+// rowCounter counts rows seen by Next and reports them once on Close.
+type rowCounter struct {
+	ctx      context.Context
+	sql      string
+	count    int64
+	reported bool
+}
+
+func newRowCounter(ctx context.Context, sql string) rowCounter {
+	return rowCounter{ctx: ctx, sql: sql}
+}
+
+func (c *rowCounter) observe(hasRow bool) bool {
+	if hasRow {
+		c.count++
+	}
+	return hasRow
+}
+
+func (c *rowCounter) report() {
+	if c.reported || c.ctx == nil {
+		return
+	}
+	c.reported = true
+	reportRowMetrics(c.ctx, RowMetrics{
+		Operation:    "query",
+		SQL:          c.sql,
+		RowsAffected: -1,
+		RowsReturned: c.count,
+	})
+}
@@ -0,0 +1,191 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"time"
+
+	"github.com/golang-migrate/migrate/v4"
+	migratedb "github.com/golang-migrate/migrate/v4/database"
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/quantumauth-io/quantum-go-utils/log"
+)
+
+const (
+	migrationLockTable = "schema_migrations_lock"
+
+	// The holder refreshes acquired_at every migrationLockHeartbeat; a lock not refreshed
+	// for migrationLockStaleAfter belongs to a replica that died mid-migration.
+	migrationLockHeartbeat  = 1 * time.Minute
+	migrationLockStaleAfter = 15 * time.Minute
+	migrationLockPollEvery  = 2 * time.Second
+)
+
+// migrationLock is a row in a dedicated table rather than pg_advisory_lock, because
+// CockroachDB accepts the advisory lock functions but doesn't actually lock.
+type migrationLock struct {
+	db     *sql.DB
+	holder string
+
+	// recovered is set when the lock was taken over from a holder that stopped
+	// heartbeating, i.e. a dirty migration state may be that holder's leftover.
+	recovered bool
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// acquireMigrationLock blocks until this process holds the migration lock or ctx ends.
+func acquireMigrationLock(ctx context.Context, connectionString string) (*migrationLock, error) {
+	db, err := sql.Open("postgres", connectionString)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open migration lock connection")
+	}
+
+	if _, err := db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS `+migrationLockTable+` (
+		id          INT PRIMARY KEY,
+		holder      TEXT NOT NULL,
+		acquired_at TIMESTAMPTZ NOT NULL
+	)`); err != nil {
+		_ = db.Close()
+		return nil, errors.Wrap(err, "failed to create migration lock table")
+	}
+
+	host, _ := os.Hostname()
+	lock := &migrationLock{db: db, holder: host + "/" + uuid.NewString()}
+
+	for waited := false; ; waited = true {
+		acquired, err := lock.tryAcquire(ctx)
+		if err != nil {
+			_ = db.Close()
+			return nil, err
+		}
+		if acquired {
+			lock.stop = make(chan struct{})
+			lock.done = make(chan struct{})
+			go lock.heartbeat()
+			return lock, nil
+		}
+
+		if !waited {
+			log.Info("Waiting for another replica to finish migrating", "table", migrationLockTable)
+		}
+		select {
+		case <-ctx.Done():
+			_ = db.Close()
+			return nil, errors.Wrap(ctx.Err(), "timed out waiting for migration lock")
+		case <-time.After(migrationLockPollEvery):
+		}
+	}
+}
+
+func (l *migrationLock) tryAcquire(ctx context.Context) (bool, error) {
+	// Taking over a stale lock is a single UPDATE, so exactly one waiter wins it and
+	// knows the previous holder died.
+	staleBefore := time.Now().Add(-migrationLockStaleAfter)
+	res, err := l.db.ExecContext(ctx,
+		`UPDATE `+migrationLockTable+` SET holder = $1, acquired_at = $2 WHERE id = 1 AND acquired_at < $3`,
+		l.holder, time.Now(), staleBefore)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to take over stale migration lock")
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return false, errors.Wrap(err, "failed to take over stale migration lock")
+	} else if n == 1 {
+		l.recovered = true
+		log.Warn("Took over a stale migration lock from a replica that stopped responding", "holder", l.holder)
+		return true, nil
+	}
+
+	res, err = l.db.ExecContext(ctx,
+		`INSERT INTO `+migrationLockTable+` (id, holder, acquired_at) VALUES (1, $1, $2) ON CONFLICT (id) DO NOTHING`,
+		l.holder, time.Now())
+	if err != nil {
+		return false, errors.Wrap(err, "failed to acquire migration lock")
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, errors.Wrap(err, "failed to acquire migration lock")
+	}
+	return n == 1, nil
+}
+
+// heartbeat keeps acquired_at fresh until release, so a long migration isn't mistaken
+// for a dead one.
+func (l *migrationLock) heartbeat() {
+	defer close(l.done)
+
+	ticker := time.NewTicker(migrationLockHeartbeat)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-ticker.C:
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), migrationLockHeartbeat)
+		res, err := l.db.ExecContext(ctx,
+			`UPDATE `+migrationLockTable+` SET acquired_at = $1 WHERE id = 1 AND holder = $2`, time.Now(), l.holder)
+		cancel()
+		if err != nil {
+			log.Warn("Failed to refresh migration lock", "error", err)
+			continue
+		}
+		if n, err := res.RowsAffected(); err == nil && n == 0 {
+			log.Error("Lost the migration lock while migrating; another replica may be migrating concurrently",
+				"holder", l.holder)
+			return
+		}
+	}
+}
+
+// release uses a fresh ctx so the lock is dropped even when the migration ctx was cancelled.
+func (l *migrationLock) release() {
+	close(l.stop)
+	<-l.done
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if _, err := l.db.ExecContext(ctx,
+		`DELETE FROM `+migrationLockTable+` WHERE id = 1 AND holder = $1`, l.holder); err != nil {
+		log.Warn("Failed to release migration lock", "error", err)
+	}
+	_ = l.db.Close()
+}
+
+// clearDirtyMigration handles a dirty flag left by a crashed migration. It is only called
+// with DatabaseSettings.MigrationForceDirty set, right after taking over the lock from a
+// dead holder, so the dirty flag is that holder's and nobody else is mid-migration. The
+// version is forced back to the previous one so the failed migration is re-applied by Up.
+func clearDirtyMigration(m *migrate.Migrate, src source.Driver) error {
+	version, dirty, err := m.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to read migration version")
+	}
+	if !dirty {
+		return nil
+	}
+
+	forceTo := migratedb.NilVersion
+	if prev, err := src.Prev(version); err == nil {
+		forceTo = int(prev)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return errors.Wrapf(err, "failed to find migration before dirty version %d", version)
+	}
+
+	log.Error("Forcing migration version back from a dirty state left by a crashed migration; "+
+		"the migration will be re-applied, check it is safe to re-run",
+		"dirtyVersion", version, "forcedTo", forceTo)
+	if err := m.Force(forceTo); err != nil {
+		return errors.Wrapf(err, "failed to clear dirty migration version %d", version)
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+package database
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultPageLimit = 50
+	maxPageLimit     = 1000
+
+	cursorKeyPrefix    = "k:"
+	cursorOffsetPrefix = "o:"
+)
+
+var (
+	ErrInvalidCursor = errors.New("invalid pagination cursor")
+	ErrNoPageOrder   = errors.New("offset pagination needs PageParams.OrderBy")
+)
+
+// PageParams selects a page for Paginate.
+//
+// With KeyColumn set, pages are keyset-paginated on that column, which must be unique
+// and selected by the base query (e.g. "id"); cost doesn't grow with depth. Without it,
+// pages use OFFSET, which is simpler but rescans every skipped row, and OrderBy is
+// required: without a total order the database may return rows in a different order
+// for each page. End OrderBy with a unique column so ties don't straddle pages.
+type PageParams struct {
+	Limit      int    // rows per page; default 50, capped at 1000
+	KeyColumn  string // trusted identifier, interpolated into the SQL
+	Descending bool   // keyset only: newest/highest keys first
+	OrderBy    string // offset only: trusted ORDER BY list, e.g. "created_at DESC, id DESC"
+	Cursor     string // from the previous page; empty for the first page
+}
+
+// Paginate runs baseSQL (placeholders $1..$len(args)) as a subquery and returns one page
+// of it, plus the cursor for the next page ("" on the last page). Any ORDER BY in
+// baseSQL is superseded by the key order in keyset mode and by OrderBy in offset mode.
+//
+// Offset mode counts the rows of the page window with LIMIT n+1, to detect a next page,
+// then reads the page in the same order.
+//
+// Keyset mode first reads the page's keys (LIMIT n+1, to detect a next page), then the
+// rows between the cursor and the page's last key, so the cursor always matches the last
+// row returned: rows inserted concurrently may make a page slightly longer, but nothing
+// is skipped or repeated.
+func Paginate(ctx context.Context, db QuantumAuthDatabase, baseSQL string, p PageParams, args ...interface{}) (QuantumAuthDatabaseRows, string, error) {
+	limit := p.Limit
+	if limit <= 0 {
+		limit = defaultPageLimit
+	}
+	limit = min(limit, maxPageLimit)
+
+	if p.KeyColumn == "" {
+		if strings.TrimSpace(p.OrderBy) == "" {
+			return nil, "", ErrNoPageOrder
+		}
+		return paginateOffset(ctx, db, baseSQL, limit, p.OrderBy, p.Cursor, args)
+	}
+	return paginateKeyset(ctx, db, baseSQL, limit, p, args)
+}
+
+func paginateOffset(ctx context.Context, db QuantumAuthDatabase, baseSQL string, limit int, orderBy string, cursor string, args []interface{}) (QuantumAuthDatabaseRows, string, error) {
+	offset := 0
+	if cursor != "" {
+		v, err := decodeCursor(cursor, cursorOffsetPrefix)
+		if err != nil {
+			return nil, "", err
+		}
+		offset, err = strconv.Atoi(v)
+		if err != nil || offset < 0 {
+			return nil, "", ErrInvalidCursor
+		}
+	}
+
+	var n int
+	row, err := db.QueryRow(ctx, fmt.Sprintf("SELECT COUNT(*) FROM (SELECT 1 FROM (%s) AS page ORDER BY %s LIMIT %d OFFSET %d) AS probe",
+		baseSQL, orderBy, limit+1, offset), args...)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "failed to count page rows")
+	}
+	if err := row.Scan(&n); err != nil {
+		return nil, "", errors.Wrap(err, "failed to count page rows")
+	}
+
+	rows, err := db.Query(ctx,
+		fmt.Sprintf("SELECT * FROM (%s) AS page ORDER BY %s LIMIT %d OFFSET %d", baseSQL, orderBy, limit, offset), args...)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "failed to query page")
+	}
+
+	next := ""
+	if n > limit {
+		next = encodeCursor(cursorOffsetPrefix, strconv.Itoa(offset+limit))
+	}
+	return rows, next, nil
+}
+
+func paginateKeyset(ctx context.Context, db QuantumAuthDatabase, baseSQL string, limit int, p PageParams, args []interface{}) (QuantumAuthDatabaseRows, string, error) {
+	key := "page." + p.KeyColumn
+	cmp, upTo, order := ">", "<=", "ASC"
+	if p.Descending {
+		cmp, upTo, order = "<", ">=", "DESC"
+	}
+
+	// Keys travel as text and bind back to the key column's type on the way in.
+	where := "TRUE"
+	keyArgs := append([]interface{}(nil), args...)
+	if p.Cursor != "" {
+		after, err := decodeCursor(p.Cursor, cursorKeyPrefix)
+		if err != nil {
+			return nil, "", err
+		}
+		keyArgs = append(keyArgs, after)
+		where = fmt.Sprintf("%s %s $%d", key, cmp, len(keyArgs))
+	}
+
+	keyRows, err := db.Query(ctx, fmt.Sprintf("SELECT CAST(%s AS TEXT) FROM (%s) AS page WHERE %s ORDER BY %s %s LIMIT %d",
+		key, baseSQL, where, key, order, limit+1), keyArgs...)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "failed to query page keys")
+	}
+	var keys []string
+	for keyRows.Next() {
+		var k string
+		if err := keyRows.Scan(&k); err != nil {
+			_ = keyRows.Close()
+			return nil, "", errors.Wrap(err, "failed to scan page key")
+		}
+		keys = append(keys, k)
+	}
+	err = keyRows.Err()
+	_ = keyRows.Close()
+	if err != nil {
+		return nil, "", errors.Wrap(err, "failed to read page keys")
+	}
+
+	if len(keys) == 0 {
+		rows, err := db.Query(ctx, fmt.Sprintf("SELECT * FROM (%s) AS page WHERE FALSE", baseSQL), args...)
+		if err != nil {
+			return nil, "", errors.Wrap(err, "failed to query page")
+		}
+		return rows, "", nil
+	}
+
+	hasMore := len(keys) > limit
+	last := keys[min(len(keys), limit)-1]
+
+	rowArgs := append(keyArgs, last)
+	bound := fmt.Sprintf("%s %s $%d", key, upTo, len(rowArgs))
+	rows, err := db.Query(ctx, fmt.Sprintf("SELECT * FROM (%s) AS page WHERE %s AND %s ORDER BY %s %s",
+		baseSQL, where, bound, key, order), rowArgs...)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "failed to query page")
+	}
+
+	next := ""
+	if hasMore {
+		next = encodeCursor(cursorKeyPrefix, last)
+	}
+	return rows, next, nil
+}
+
+// Cursors are opaque to callers: base64url of a mode prefix and the value.
+func encodeCursor(prefix string, value string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(prefix + value))
+}
+
+func decodeCursor(cursor string, prefix string) (string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || !strings.HasPrefix(string(raw), prefix) {
+		return "", ErrInvalidCursor
+	}
+	return strings.TrimPrefix(string(raw), prefix), nil
+}
@@ -0,0 +1,124 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/pkg/errors"
+	"github.com/quantumauth-io/quantum-go-utils/log"
+)
+
+// ErrPoolExhausted means every pooled connection was checked out for the whole time the
+// caller was willing to wait, as opposed to the database being unreachable.
+var ErrPoolExhausted = errors.New("database connection pool exhausted")
+
+// poolAcquireErr rewrites a ctx error hit while waiting on a saturated pgx pool into
+// ErrPoolExhausted with the pool stats in the message. Both ErrPoolExhausted and the
+// original ctx error still match errors.Is. Other errors are returned unchanged.
+func poolAcquireErr(pool *pgxpool.Pool, err error) error {
+	if err == nil || pool == nil {
+		return err
+	}
+	if !errors.Is(err, context.DeadlineExceeded) && !errors.Is(err, context.Canceled) {
+		return err
+	}
+
+	stat := pool.Stat()
+	if stat.AcquiredConns() < stat.MaxConns() {
+		// There was room in the pool, so the wait was on dialing the database instead.
+		return err
+	}
+	return fmt.Errorf("%w (acquired %d/%d, idle %d, constructing %d, total acquires %d, empty acquires %d): %w",
+		ErrPoolExhausted, stat.AcquiredConns(), stat.MaxConns(), stat.IdleConns(),
+		stat.ConstructingConns(), stat.AcquireCount(), stat.EmptyAcquireCount(), err)
+}
+
+// PoolStats is a snapshot of the connection pool. The int64 counters are cumulative, so
+// a jump between two snapshots shows connection churn, e.g. while CockroachDB nodes are
+// rolled and the pool silently replaces dead connections.
+type PoolStats struct {
+	MaxConns      int32
+	TotalConns    int32
+	AcquiredConns int32
+	IdleConns     int32
+
+	NewConns           int64 // connections opened since the pool was created
+	FailedHealthChecks int64 // idle connections found dead on acquire and discarded
+	LifetimeClosed     int64 // connections closed for exceeding the max lifetime
+	IdleClosed         int64 // connections closed for sitting idle too long
+}
+
+// poolChurn counts connection lifecycle events pgxpool.Stat doesn't: every connection
+// opened (Stat's NewConnsCount only counts those opened to keep MinConns), and dead idle
+// connections discarded on acquire. A connection that dies while checked out is dropped
+// by Release without any hook, so it shows up only as a new connection.
+type poolChurn struct {
+	newConns           atomic.Int64
+	failedHealthChecks atomic.Int64
+}
+
+// instrument installs the lifecycle hooks on cfg. Existing hooks are kept and run first.
+// It deliberately leaves AfterRelease alone: setting one makes every Release run it on a
+// new goroutine.
+func (c *poolChurn) instrument(cfg *pgxpool.Config) {
+	afterConnect := cfg.AfterConnect
+	cfg.AfterConnect = func(ctx context.Context, conn *pgx.Conn) error {
+		if afterConnect != nil {
+			if err := afterConnect(ctx, conn); err != nil {
+				return err
+			}
+		}
+		if n := c.newConns.Add(1); n > 1 {
+			log.Debug("Database pool opened a new connection", "newConns", n)
+		}
+		return nil
+	}
+
+	beforeAcquire := cfg.BeforeAcquire
+	cfg.BeforeAcquire = func(ctx context.Context, conn *pgx.Conn) bool {
+		if conn.IsClosed() {
+			c.failedHealthChecks.Add(1)
+			return false
+		}
+		return beforeAcquire == nil || beforeAcquire(ctx, conn)
+	}
+}
+
+func pgxPoolStats(pool *pgxpool.Pool, churn *poolChurn) PoolStats {
+	stat := pool.Stat()
+	out := PoolStats{
+		MaxConns:       stat.MaxConns(),
+		TotalConns:     stat.TotalConns(),
+		AcquiredConns:  stat.AcquiredConns(),
+		IdleConns:      stat.IdleConns(),
+		NewConns:       stat.NewConnsCount(),
+		LifetimeClosed: stat.MaxLifetimeDestroyCount(),
+		IdleClosed:     stat.MaxIdleDestroyCount(),
+	}
+	if churn != nil {
+		out.NewConns = churn.newConns.Load()
+		out.FailedHealthChecks = churn.failedHealthChecks.Load()
+	}
+	return out
+}
+
+// sqlPoolStats maps database/sql stats. database/sql has no connect hook and drops bad
+// connections without counting them, so NewConns is a lower bound (open connections plus
+// those closed for idleness or lifetime) and FailedHealthChecks is always 0.
+func sqlPoolStats(db *sql.DB) PoolStats {
+	stat := db.Stats()
+	return PoolStats{
+		MaxConns:      int32(stat.MaxOpenConnections),
+		TotalConns:    int32(stat.OpenConnections),
+		AcquiredConns: int32(stat.InUse),
+		IdleConns:     int32(stat.Idle),
+		NewConns:      int64(stat.OpenConnections) + stat.MaxIdleClosed + stat.MaxIdleTimeClosed + stat.MaxLifetimeClosed,
+
+		LifetimeClosed: stat.MaxLifetimeClosed,
+		IdleClosed:     stat.MaxIdleClosed + stat.MaxIdleTimeClosed,
+	}
+}
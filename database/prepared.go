@@ -0,0 +1,104 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/quantumauth-io/quantum-go-utils/retry"
+)
+
+// preparedBackend is the driver-specific half of a PreparedStatement.
+type preparedBackend interface {
+	exec(ctx context.Context, arguments ...interface{}) (QuantumAuthDatabaseExecResult, error)
+	query(ctx context.Context, arguments ...interface{}) (QuantumAuthDatabaseRows, error)
+	queryRow(ctx context.Context, arguments ...interface{}) (QuantumAuthDatabaseRow, error)
+	close() error
+}
+
+// PreparedStatement is a reusable server-side prepared query.
+// Exec and Query are retried like the database methods; QueryRow is not (see QueryRow on the database).
+type PreparedStatement struct {
+	name      string
+	sql       string
+	backend   preparedBackend
+	retryable func(error) bool
+	scope     *shutdownScope
+}
+
+func (ps *PreparedStatement) Name() string { return ps.name }
+func (ps *PreparedStatement) SQL() string  { return ps.sql }
+
+func (ps *PreparedStatement) Exec(ctx context.Context, arguments ...interface{}) (QuantumAuthDatabaseExecResult, error) {
+	ctx, done, err := ps.scope.enter(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+
+	retryCfg := retry.DefaultConfig()
+	retryCfg.MaxDelayBeforeRetrying = 1 * time.Second
+	retryCfg.MaxNumRetries = defaultMaxRetry
+
+	result, err := retry.Retry(ctx, retryCfg,
+		func(context.Context) ([]interface{}, error) {
+			res, err := ps.backend.exec(ctx, arguments...)
+			if err != nil {
+				return nil, err
+			}
+			return []interface{}{res}, nil
+		},
+		ps.retryable,
+		"Prepared Statement Exec: "+ps.name,
+	)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to execute prepared statement %s after retries", ps.name)
+	}
+	return result[0].(QuantumAuthDatabaseExecResult), nil
+}
+
+func (ps *PreparedStatement) Query(ctx context.Context, arguments ...interface{}) (QuantumAuthDatabaseRows, error) {
+	ctx, done, err := ps.scope.enter(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	retryCfg := retry.DefaultConfig()
+	retryCfg.MaxDelayBeforeRetrying = 1 * time.Second
+	retryCfg.MaxNumRetries = defaultMaxRetry
+
+	result, err := retry.Retry(ctx, retryCfg,
+		func(context.Context) ([]interface{}, error) {
+			rows, err := ps.backend.query(ctx, arguments...)
+			if err != nil {
+				return nil, err
+			}
+			return []interface{}{rows}, nil
+		},
+		ps.retryable,
+		"Prepared Statement Query: "+ps.name,
+	)
+	if err != nil {
+		done()
+		return nil, errors.Wrapf(err, "failed to query prepared statement %s after retries", ps.name)
+	}
+	return &scopedRows{QuantumAuthDatabaseRows: result[0].(QuantumAuthDatabaseRows), done: done}, nil
+}
+
+func (ps *PreparedStatement) QueryRow(ctx context.Context, arguments ...interface{}) (QuantumAuthDatabaseRow, error) {
+	ctx, done, err := ps.scope.enter(ctx)
+	if err != nil {
+		return nil, err
+	}
+	row, err := ps.backend.queryRow(ctx, arguments...)
+	if err != nil {
+		done()
+		return nil, err
+	}
+	return &scopedRow{row: row, done: done}, nil
+}
+
+// Close releases the statement. It must not be used afterwards.
+func (ps *PreparedStatement) Close() error {
+	return ps.backend.close()
+}
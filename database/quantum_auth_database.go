@@ -12,10 +12,20 @@ import (
 type QuantumAuthDatabase interface {
 	Exec(ctx context.Context, sql string, arguments ...interface{}) (QuantumAuthDatabaseExecResult, error)
 	QueryRow(ctx context.Context, sql string, arguments ...interface{}) (QuantumAuthDatabaseRow, error)
+	Exists(ctx context.Context, sql string, arguments ...interface{}) (bool, error)
+	Count(ctx context.Context, sql string, arguments ...interface{}) (int64, error)
 	Query(ctx context.Context, sql string, arguments ...interface{}) (QuantumAuthDatabaseRows, error)
+	QueryCursor(ctx context.Context, batchSize int, sql string, arguments ...interface{}) (*Cursor, error)
+	Prepare(ctx context.Context, name string, sql string) (*PreparedStatement, error)
+	ExecScript(ctx context.Context, script string) error
+	DeleteInBatches(ctx context.Context, table string, whereClause string, batchSize int, arguments ...interface{}) (int64, error)
 	GetTransaction(ctx context.Context) (QuantumAuthDatabaseTransaction, error)
+	WithDedicatedConn(ctx context.Context) (context.Context, func(), error)
 	Close() error
+	Shutdown(ctx context.Context) error
 	Ping(ctx context.Context) error
+	WarmUp(ctx context.Context) error
+	Stats() PoolStats
 	MigrateWithIOFS(ctx context.Context, source source.Driver) error
 }
 
@@ -0,0 +1,81 @@
+package database
+
+import (
+	"context"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// connectRamp spreads the first n connections of a pool over rampUp instead of opening
+// them all at once: connection i waits until start + i*rampUp/n plus up to one interval
+// of jitter, so replicas deployed together don't hit the database in lockstep. Once
+// rampUp has passed, connections open immediately.
+type connectRamp struct {
+	start    time.Time
+	rampUp   time.Duration
+	interval time.Duration
+
+	mu   sync.Mutex
+	next int
+}
+
+// newConnectRamp returns nil (no pacing) when rampUp is zero.
+func newConnectRamp(rampUp time.Duration, n int) *connectRamp {
+	if rampUp <= 0 || n <= 0 {
+		return nil
+	}
+	return &connectRamp{start: time.Now(), rampUp: rampUp, interval: rampUp / time.Duration(n)}
+}
+
+func (r *connectRamp) wait(ctx context.Context) error {
+	if r == nil {
+		return nil
+	}
+
+	r.mu.Lock()
+	slot := r.next
+	r.next++
+	r.mu.Unlock()
+
+	offset := time.Duration(slot) * r.interval
+	if offset >= r.rampUp {
+		return nil
+	}
+	if r.interval > 0 {
+		offset += rand.N(r.interval)
+	}
+
+	delay := time.Until(r.start.Add(offset))
+	if delay <= 0 {
+		return nil
+	}
+	t := time.NewTimer(delay)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// instrument paces new pgx connections through the ramp.
+func (r *connectRamp) instrument(cfg *pgxpool.Config) {
+	if r == nil {
+		return
+	}
+	beforeConnect := cfg.BeforeConnect
+	cfg.BeforeConnect = func(ctx context.Context, connCfg *pgx.ConnConfig) error {
+		if err := r.wait(ctx); err != nil {
+			return err
+		}
+		if beforeConnect != nil {
+			return beforeConnect(ctx, connCfg)
+		}
+		return nil
+	}
+}
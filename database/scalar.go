@@ -0,0 +1,34 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/quantumauth-io/quantum-go-utils/retry"
+)
+
+// queryScalar runs sql, which must return exactly one row with one column, and scans
+// that value into dest. QueryRow and Scan are retried together, as a failed attempt
+// only shows up at Scan.
+func queryScalar(ctx context.Context, db QuantumAuthDatabase, retryable func(error) bool, name string, dest interface{}, sql string, arguments ...interface{}) error {
+	retryCfg := retry.DefaultConfig()
+	retryCfg.MaxDelayBeforeRetrying = 1 * time.Second
+	retryCfg.MaxNumRetries = defaultMaxRetry
+
+	_, err := retry.Retry(ctx, retryCfg,
+		func(context.Context) ([]interface{}, error) {
+			row, err := db.QueryRow(ctx, sql, arguments...)
+			if err != nil {
+				return nil, err
+			}
+			return nil, ConditionallyConvertToErrNoRows(row.Scan(dest))
+		},
+		retryable,
+		name,
+	)
+	if err != nil {
+		return errors.Wrapf(err, "failed to query %s after retries", sql)
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package database
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// execScript runs each statement of script in order inside one transaction, so a failing
+// statement leaves nothing applied. Statements that Postgres refuses inside a transaction
+// (CREATE DATABASE, CREATE INDEX CONCURRENTLY, VACUUM) can't be used in a script.
+func execScript(ctx context.Context, db QuantumAuthDatabase, script string) error {
+	statements := splitSQLStatements(script)
+	if len(statements) == 0 {
+		return nil
+	}
+
+	tx, err := db.GetTransaction(ctx)
+	if err != nil {
+		return err
+	}
+	for i, stmt := range statements {
+		if _, err := tx.Exec(ctx, stmt); err != nil {
+			_ = tx.Rollback(ctx)
+			return errors.Wrapf(err, "script statement %d failed: %s", i+1, stmt)
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return errors.Wrap(err, "failed to commit script")
+	}
+	return nil
+}
+
+// splitSQLStatements splits script on top-level semicolons. Semicolons inside quoted
+// strings ('...', E'...'), quoted identifiers ("..."), dollar-quoted bodies ($$...$$,
+// $tag$...$tag$) and comments don't split. Empty statements are dropped.
+func splitSQLStatements(script string) []string {
+	var (
+		out   []string
+		start int
+	)
+	emit := func(end int) {
+		if stmt := strings.TrimSpace(script[start:end]); stmt != "" && !onlyComments(stmt) {
+			out = append(out, stmt)
+		}
+	}
+
+	for i := 0; i < len(script); {
+		switch c := script[i]; {
+		case c == ';':
+			emit(i)
+			i++
+			start = i
+		case c == '\'':
+			escapes := i > 0 && (script[i-1] == 'E' || script[i-1] == 'e') && (i < 2 || !isIdentChar(script[i-2]))
+			i = skipQuoted(script, i, '\'', escapes)
+		case c == '"':
+			i = skipQuoted(script, i, '"', false)
+		case c == '-' && strings.HasPrefix(script[i:], "--"):
+			if nl := strings.IndexByte(script[i:], '\n'); nl >= 0 {
+				i += nl + 1
+			} else {
+				i = len(script)
+			}
+		case c == '/' && strings.HasPrefix(script[i:], "/*"):
+			i = skipBlockComment(script, i)
+		case c == '$' && (i == 0 || !isIdentChar(script[i-1])):
+			if tag, ok := dollarTag(script[i:]); ok {
+				if end := strings.Index(script[i+len(tag):], tag); end >= 0 {
+					i += len(tag) + end + len(tag)
+				} else {
+					i = len(script)
+				}
+				continue
+			}
+			i++
+		default:
+			i++
+		}
+	}
+	emit(len(script))
+	return out
+}
+
+// skipQuoted returns the index after the closing quote; a doubled quote is an escaped quote.
+func skipQuoted(s string, i int, quote byte, backslashEscapes bool) int {
+	for i++; i < len(s); i++ {
+		switch {
+		case backslashEscapes && s[i] == '\\':
+			i++
+		case s[i] == quote:
+			if i+1 < len(s) && s[i+1] == quote {
+				i++
+				continue
+			}
+			return i + 1
+		}
+	}
+	return len(s)
+}
+
+// skipBlockComment handles Postgres' nested /* /* */ */ comments.
+func skipBlockComment(s string, i int) int {
+	depth := 0
+	for i < len(s) {
+		switch {
+		case strings.HasPrefix(s[i:], "/*"):
+			depth++
+			i += 2
+		case strings.HasPrefix(s[i:], "*/"):
+			depth--
+			i += 2
+			if depth == 0 {
+				return i
+			}
+		default:
+			i++
+		}
+	}
+	return len(s)
+}
+
+// dollarTag returns the opening "$tag$" at the start of s, if there is one.
+func dollarTag(s string) (string, bool) {
+	for j := 1; j < len(s); j++ {
+		if s[j] == '$' {
+			return s[:j+1], true
+		}
+		if !isIdentChar(s[j]) || (j == 1 && s[j] >= '0' && s[j] <= '9') {
+			return "", false // $1 is a placeholder, not a tag
+		}
+	}
+	return "", false
+}
+
+func isIdentChar(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c >= 0x80
+}
+
+func onlyComments(stmt string) bool {
+	for _, line := range strings.Split(stmt, "\n") {
+		if l := strings.TrimSpace(line); l != "" && !strings.HasPrefix(l, "--") {
+			return false
+		}
+	}
+	return true
+}
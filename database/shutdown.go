@@ -0,0 +1,102 @@
+package database
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+var ErrDatabaseShuttingDown = errors.New("database is shutting down")
+
+// shutdownScope is a database-owned parent context. Every operation enters the scope,
+// which ties its ctx to the scope's so Shutdown can cancel everything in flight and
+// then wait for those operations to drain.
+type shutdownScope struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu     sync.Mutex
+	closed bool
+	wg     sync.WaitGroup
+}
+
+func newShutdownScope() *shutdownScope {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &shutdownScope{ctx: ctx, cancel: cancel}
+}
+
+// enter returns a ctx that is also cancelled by shutdown, and a done func that must be
+// called exactly once when the operation (including any rows it returned) is finished.
+func (s *shutdownScope) enter(ctx context.Context) (context.Context, func(), error) {
+	if s == nil {
+		return ctx, func() {}, nil
+	}
+
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil, nil, ErrDatabaseShuttingDown
+	}
+	s.wg.Add(1)
+	s.mu.Unlock()
+
+	opCtx, cancel := context.WithCancel(ctx)
+	stop := context.AfterFunc(s.ctx, cancel)
+
+	var once sync.Once
+	return opCtx, func() {
+		once.Do(func() {
+			stop()
+			cancel()
+			s.wg.Done()
+		})
+	}, nil
+}
+
+// shutdown rejects new operations, cancels in-flight ones and waits for them to drain or ctx to expire.
+func (s *shutdownScope) shutdown(ctx context.Context) error {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	s.closed = true
+	s.mu.Unlock()
+	s.cancel()
+
+	drained := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+		return nil
+	case <-ctx.Done():
+		return errors.Wrap(ctx.Err(), "timed out waiting for in-flight database operations")
+	}
+}
+
+// scopedRows ends the operation's scope when the rows are closed.
+type scopedRows struct {
+	QuantumAuthDatabaseRows
+	done func()
+}
+
+func (r *scopedRows) Close() error {
+	defer r.done()
+	return r.QuantumAuthDatabaseRows.Close()
+}
+
+// scopedRow ends the operation's scope once the row has been scanned.
+type scopedRow struct {
+	row  QuantumAuthDatabaseRow
+	done func()
+}
+
+func (r *scopedRow) Scan(dest ...interface{}) error {
+	defer r.done()
+	return r.row.Scan(dest...)
+}
@@ -0,0 +1,95 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/pkg/errors"
+)
+
+// warmUpTarget is how many connections WarmUp opens: MinPoolSize (or its default),
+// capped at limit when limit > 0.
+func warmUpTarget(settings DatabaseSettings, limit uint) int {
+	n := settings.MinPoolSize
+	if n == 0 {
+		n = defaultMinDBPoolSize
+	}
+	if limit > 0 && n > limit {
+		n = limit
+	}
+	return int(n)
+}
+
+// WarmUp opens MinPoolSize connections and runs SELECT 1 on each, so the TLS handshake
+// and auth are paid before the service reports ready instead of by the first requests.
+// All connections are held at once so each one is distinct, then returned to the pool.
+// With ConnectRampUp set, the connections are paced over that window.
+func (db *AuroraPGXDatabase) WarmUp(ctx context.Context) error {
+	ctx, done, err := db.scope.enter(ctx)
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	n := warmUpTarget(db.settings, uint(db.dbPool.Config().MaxConns))
+	conns := make([]*pgxpool.Conn, 0, n)
+	defer func() {
+		for _, c := range conns {
+			c.Release()
+		}
+	}()
+
+	for i := 0; i < n; i++ {
+		conn, err := db.dbPool.Acquire(ctx)
+		if err != nil {
+			return errors.Wrapf(poolAcquireErr(db.dbPool, err), "warm-up connection %d/%d", i+1, n)
+		}
+		conns = append(conns, conn)
+		if _, err := conn.Exec(ctx, "SELECT 1"); err != nil {
+			return errors.Wrapf(err, "warm-up connection %d/%d", i+1, n)
+		}
+	}
+	return nil
+}
+
+// WarmUp opens MinPoolSize connections (capped at MaxIdleConnections, since database/sql
+// closes any extra idle ones on return, and at PoolSize) and runs SELECT 1 on each; see
+// AuroraPGXDatabase.WarmUp.
+func (db *CockroachSQLDatabase) WarmUp(ctx context.Context) error {
+	ctx, done, err := db.scope.enter(ctx)
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	limit := db.settings.MaxIdleConnections
+	if limit == 0 {
+		limit = defaultIdlePoolSize
+	}
+	if open := uint(db.dbPool.Stats().MaxOpenConnections); open > 0 && open < limit {
+		limit = open
+	}
+	n := warmUpTarget(db.settings, limit)
+	conns := make([]*sql.Conn, 0, n)
+	defer func() {
+		for _, c := range conns {
+			_ = c.Close()
+		}
+	}()
+
+	for i := 0; i < n; i++ {
+		if err := db.ramp.wait(ctx); err != nil {
+			return errors.Wrapf(err, "warm-up connection %d/%d", i+1, n)
+		}
+		conn, err := db.dbPool.Conn(ctx)
+		if err != nil {
+			return errors.Wrapf(err, "warm-up connection %d/%d", i+1, n)
+		}
+		conns = append(conns, conn)
+		if _, err := conn.ExecContext(ctx, "SELECT 1"); err != nil {
+			return errors.Wrapf(err, "warm-up connection %d/%d", i+1, n)
+		}
+	}
+	return nil
+}
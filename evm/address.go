@@ -0,0 +1,19 @@
+package evm
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// ComputeCreateAddress returns the address a CREATE from sender at nonce deploys to:
+// keccak256(rlp([sender, nonce]))[12:].
+func ComputeCreateAddress(sender common.Address, nonce uint64) common.Address {
+	return crypto.CreateAddress(sender, nonce)
+}
+
+// ComputeCreate2Address returns the address a CREATE2 from sender (usually a factory
+// contract) deploys to: keccak256(0xff ++ sender ++ salt ++ initCodeHash)[12:].
+// initCodeHash is keccak256 of the creation bytecode including constructor args.
+func ComputeCreate2Address(sender common.Address, salt [32]byte, initCodeHash [32]byte) common.Address {
+	return crypto.CreateAddress2(sender, salt, initCodeHash[:])
+}
@@ -0,0 +1,86 @@
+package evm
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestComputeCreateAddress(t *testing.T) {
+	sender := common.HexToAddress("0x6ac7ea33f8831ea9dcc53393aaa88b25a785dbf0")
+	tests := []struct {
+		nonce uint64
+		want  string
+	}{
+		{0, "0xcd234a471b72ba2f1ccf0a70fcaba648a5eecd8d"},
+		{1, "0x343c43a37d37dff08ae8c4a11544c718abb4fcf8"},
+		{2, "0xf778b86fa74e846c4f0a1fbd1335fe81c00a0c91"},
+	}
+	for _, tt := range tests {
+		if got := ComputeCreateAddress(sender, tt.nonce); got != common.HexToAddress(tt.want) {
+			t.Errorf("nonce %d: got %s, want %s", tt.nonce, got.Hex(), tt.want)
+		}
+	}
+}
+
+// The examples from EIP-1014.
+func TestComputeCreate2Address(t *testing.T) {
+	tests := []struct {
+		sender, salt, initCode, want string
+	}{
+		{
+			"0x0000000000000000000000000000000000000000",
+			"0x0000000000000000000000000000000000000000000000000000000000000000",
+			"0x00",
+			"0x4D1A2e2bB4F88F0250f26Ffff098B0b30B26BF38",
+		},
+		{
+			"0xdeadbeef00000000000000000000000000000000",
+			"0x0000000000000000000000000000000000000000000000000000000000000000",
+			"0x00",
+			"0xB928f69Bb1D91Cd65274e3c79d8986362984fDA3",
+		},
+		{
+			"0xdeadbeef00000000000000000000000000000000",
+			"0x000000000000000000000000feed000000000000000000000000000000000000",
+			"0x00",
+			"0xD04116cDd17beBE565EB2422F2497E06cC1C9833",
+		},
+		{
+			"0x0000000000000000000000000000000000000000",
+			"0x0000000000000000000000000000000000000000000000000000000000000000",
+			"0xdeadbeef",
+			"0x70f2b2914A2a4b783FaEFb75f459A580616Fcb5e",
+		},
+		{
+			"0x00000000000000000000000000000000deadbeef",
+			"0x00000000000000000000000000000000000000000000000000000000cafebabe",
+			"0xdeadbeef",
+			"0x60f3f640a8508fC6a86d45DF051962668E1e8AC7",
+		},
+		{
+			"0x00000000000000000000000000000000deadbeef",
+			"0x00000000000000000000000000000000000000000000000000000000cafebabe",
+			"0xdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeef",
+			"0x1d8bfDC5D46DC4f61D6b6115972536eBE6A8854C",
+		},
+		{
+			"0x0000000000000000000000000000000000000000",
+			"0x0000000000000000000000000000000000000000000000000000000000000000",
+			"0x",
+			"0xE33C0C7F7df4809055C3ebA6c09CFe4BaF1BD9e0",
+		},
+	}
+	for i, tt := range tests {
+		got := ComputeCreate2Address(
+			common.HexToAddress(tt.sender),
+			common.HexToHash(tt.salt),
+			crypto.Keccak256Hash(hexutil.MustDecode(tt.initCode)),
+		)
+		if got != common.HexToAddress(tt.want) {
+			t.Errorf("example %d: got %s, want %s", i, got.Hex(), tt.want)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package evm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// contractArtifact covers both Hardhat ("bytecode": "0x...") and Foundry
+// ("bytecode": {"object": "0x..."}) compiler output.
+type contractArtifact struct {
+	ABI      json.RawMessage `json:"abi"`
+	Bytecode json.RawMessage `json:"bytecode"`
+}
+
+// parseArtifact returns the ABI and creation bytecode from a compiled contract artifact.
+func parseArtifact(artifactJSON []byte) (abi.ABI, []byte, error) {
+	var art contractArtifact
+	if err := json.Unmarshal(artifactJSON, &art); err != nil {
+		return abi.ABI{}, nil, fmt.Errorf("evm: parse artifact: %w", err)
+	}
+	if len(art.ABI) == 0 || len(art.Bytecode) == 0 {
+		return abi.ABI{}, nil, errors.New("evm: artifact needs both abi and bytecode")
+	}
+
+	parsed, err := abi.JSON(bytes.NewReader(art.ABI))
+	if err != nil {
+		return abi.ABI{}, nil, fmt.Errorf("evm: parse artifact ABI: %w", err)
+	}
+
+	var code string
+	if err := json.Unmarshal(art.Bytecode, &code); err != nil {
+		var obj struct {
+			Object string `json:"object"`
+		}
+		if err := json.Unmarshal(art.Bytecode, &obj); err != nil {
+			return abi.ABI{}, nil, fmt.Errorf("evm: artifact bytecode: %w", err)
+		}
+		code = obj.Object
+	}
+	if !strings.HasPrefix(code, "0x") {
+		code = "0x" + code
+	}
+	if strings.Contains(code, "__") {
+		return abi.ABI{}, nil, errors.New("evm: artifact bytecode has unlinked library placeholders")
+	}
+	bytecode, err := hexutil.Decode(code)
+	if err != nil {
+		return abi.ABI{}, nil, fmt.Errorf("evm: artifact bytecode: %w", err)
+	}
+	if len(bytecode) == 0 {
+		return abi.ABI{}, nil, errors.New("evm: artifact bytecode is empty (abstract contract or interface?)")
+	}
+	return parsed, bytecode, nil
+}
+
+// DeployArtifact deploys a contract from a Hardhat/Foundry artifact (its "abi" and
+// "bytecode" fields) with constructor args, commits the block and returns the contract
+// address and a BoundContract for further calls. A failed deployment is an error.
+func (c *SimulatedBlockchainClient) DeployArtifact(ctx context.Context, auth *bind.TransactOpts, artifactJSON []byte, args ...interface{}) (common.Address, *bind.BoundContract, error) {
+	parsed, bytecode, err := parseArtifact(artifactJSON)
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+
+	opts := *auth
+	if opts.Context == nil {
+		opts.Context = ctx
+	}
+	addr, tx, bound, err := bind.DeployContract(&opts, parsed, bytecode, c, args...)
+	if err != nil {
+		return common.Address{}, nil, fmt.Errorf("evm: deploy artifact: %w", err)
+	}
+
+	receipt, err := c.WaitForTransaction(ctx, tx.Hash())
+	if err != nil {
+		return common.Address{}, nil, fmt.Errorf("evm: deploy artifact receipt: %w", err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return common.Address{}, nil, fmt.Errorf("evm: deploy artifact: tx %s reverted", tx.Hash().Hex())
+	}
+	return addr, bound, nil
+}
@@ -0,0 +1,213 @@
+package evm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync/atomic"
+	"time"
+)
+
+// LoadBalanceMode selects how LoadBalancer spreads read requests.
+type LoadBalanceMode int
+
+const (
+	LoadBalanceRoundRobin  LoadBalanceMode = iota
+	LoadBalanceLeastLoaded                 // fewest requests in flight, round-robin on ties
+)
+
+// DefaultUnhealthyCooldown is how long LoadBalancer skips an endpoint after it failed.
+const DefaultUnhealthyCooldown = 30 * time.Second
+
+// pinnedMethods go to the primary endpoint: sends must reach the node whose mempool and
+// nonce view the caller relies on, and filters only exist on the node that created them.
+var pinnedMethods = map[string]bool{
+	"eth_sendRawTransaction":          true,
+	"eth_sendTransaction":             true,
+	"eth_newFilter":                   true,
+	"eth_newBlockFilter":              true,
+	"eth_newPendingTransactionFilter": true,
+	"eth_getFilterChanges":            true,
+	"eth_getFilterLogs":               true,
+	"eth_uninstallFilter":             true,
+}
+
+// LoadBalancer is an http.RoundTripper that spreads JSON-RPC requests over several HTTP
+// endpoints of the same chain, to add up the rate limits of several providers. Reads go
+// round-robin or to the least-loaded endpoint; a read that fails at the transport or
+// gets a 429/5xx is retried on the next endpoint. Writes and filter calls (see
+// pinnedMethods) always go to the primary: the first healthy endpoint in list order.
+// An endpoint that failed is skipped for Cooldown, unless no healthy one is left.
+//
+// Endpoints may serve slightly different heads, so consecutive reads can observe
+// different blocks; pin reads to a block number where that matters.
+type LoadBalancer struct {
+	Base     http.RoundTripper // nil means http.DefaultTransport
+	Cooldown time.Duration     // 0 means DefaultUnhealthyCooldown
+
+	mode      LoadBalanceMode
+	endpoints []*lbEndpoint
+	next      atomic.Uint64
+}
+
+type lbEndpoint struct {
+	idx            int
+	url            *url.URL
+	inFlight       atomic.Int64
+	unhealthyUntil atomic.Int64 // unix nanos
+}
+
+// NewLoadBalancer balances over the http(s) endpoint URLs; the first is the primary.
+func NewLoadBalancer(endpoints []string, mode LoadBalanceMode) (*LoadBalancer, error) {
+	if len(endpoints) == 0 {
+		return nil, errors.New("evm: load balancer needs at least one endpoint")
+	}
+	lb := &LoadBalancer{mode: mode}
+	for i, raw := range endpoints {
+		u, err := url.Parse(raw)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			// Don't echo the URL: provider URLs usually carry an API key.
+			return nil, fmt.Errorf("evm: load balancer endpoint %d is not an http(s) URL", i)
+		}
+		lb.endpoints = append(lb.endpoints, &lbEndpoint{idx: i, url: u})
+	}
+	return lb, nil
+}
+
+// DialLoadBalanced returns a client whose calls go through a LoadBalancer over endpoints.
+func DialLoadBalanced(ctx context.Context, endpoints []string, mode LoadBalanceMode) (*LiveBlockchainClient, error) {
+	lb, err := NewLoadBalancer(endpoints, mode)
+	if err != nil {
+		return nil, err
+	}
+	// The URL is a placeholder: the balancer replaces it per request.
+	return NewLiveBlockchainClientWithTransport(ctx, "http://evm-load-balancer", lb)
+}
+
+func (lb *LoadBalancer) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		b, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("evm: read request body: %w", err)
+		}
+		body = b
+	}
+	pinned := hasPinnedMethod(body)
+
+	tried := make([]bool, len(lb.endpoints))
+	remaining := len(lb.endpoints)
+	for {
+		e := lb.pick(pinned, tried)
+		tried[e.idx] = true
+		remaining--
+
+		resp, err := lb.send(req, e, body)
+		if err == nil && resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode < 500 {
+			return resp, nil
+		}
+		e.unhealthyUntil.Store(time.Now().Add(lb.cooldown()).UnixNano())
+		if pinned || remaining == 0 || req.Context().Err() != nil {
+			return resp, err
+		}
+		if resp != nil {
+			resp.Body.Close()
+		}
+	}
+}
+
+func (lb *LoadBalancer) send(req *http.Request, e *lbEndpoint, body []byte) (*http.Response, error) {
+	out := req.Clone(req.Context())
+	u := *e.url
+	out.URL = &u
+	out.Host = ""
+	if body != nil {
+		out.Body = io.NopCloser(bytes.NewReader(body))
+		out.ContentLength = int64(len(body))
+		out.GetBody = func() (io.ReadCloser, error) { return io.NopCloser(bytes.NewReader(body)), nil }
+	}
+
+	base := lb.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	e.inFlight.Add(1)
+	defer e.inFlight.Add(-1)
+	return base.RoundTrip(out)
+}
+
+// pick chooses among untried endpoints, preferring healthy ones.
+func (lb *LoadBalancer) pick(pinned bool, tried []bool) *lbEndpoint {
+	now := time.Now().UnixNano()
+	healthy := func(e *lbEndpoint) bool { return e.unhealthyUntil.Load() <= now }
+
+	if pinned {
+		for _, e := range lb.endpoints {
+			if healthy(e) {
+				return e
+			}
+		}
+		return lb.endpoints[0]
+	}
+
+	n := len(lb.endpoints)
+	start := int(lb.next.Add(1) % uint64(n))
+	for _, wantHealthy := range []bool{true, false} {
+		var best *lbEndpoint
+		for i := 0; i < n; i++ {
+			e := lb.endpoints[(start+i)%n]
+			if tried[e.idx] || (wantHealthy && !healthy(e)) {
+				continue
+			}
+			if lb.mode != LoadBalanceLeastLoaded {
+				return e
+			}
+			if best == nil || e.inFlight.Load() < best.inFlight.Load() {
+				best = e
+			}
+		}
+		if best != nil {
+			return best
+		}
+	}
+	return lb.endpoints[start] // unreachable while an untried endpoint remains
+}
+
+func (lb *LoadBalancer) cooldown() time.Duration {
+	if lb.Cooldown > 0 {
+		return lb.Cooldown
+	}
+	return DefaultUnhealthyCooldown
+}
+
+// hasPinnedMethod reports whether the request, or any element of a batch, calls a
+// pinned method. Bodies that don't parse are treated as reads.
+func hasPinnedMethod(body []byte) bool {
+	type call struct {
+		Method string `json:"method"`
+	}
+	var msgs []call
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		if json.Unmarshal(trimmed, &msgs) != nil {
+			return false
+		}
+	} else {
+		msgs = make([]call, 1)
+		if json.Unmarshal(trimmed, &msgs[0]) != nil {
+			return false
+		}
+	}
+	for _, m := range msgs {
+		if pinnedMethods[m.Method] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,160 @@
+package evm
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// hostRecorder answers 200 with an empty result, except for hosts listed in failing,
+// which get 503. It records the host of every request.
+type hostRecorder struct {
+	mu      sync.Mutex
+	hosts   []string
+	failing map[string]bool
+}
+
+func (r *hostRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	r.mu.Lock()
+	r.hosts = append(r.hosts, req.URL.Host)
+	fail := r.failing[req.URL.Host]
+	r.mu.Unlock()
+
+	status := http.StatusOK
+	if fail {
+		status = http.StatusServiceUnavailable
+	}
+	return &http.Response{
+		StatusCode: status,
+		Header:     http.Header{},
+		Body:       io.NopCloser(strings.NewReader(`{"jsonrpc":"2.0","id":1,"result":"0x1"}`)),
+		Request:    req,
+	}, nil
+}
+
+func (r *hostRecorder) take() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := r.hosts
+	r.hosts = nil
+	return out
+}
+
+func newTestBalancer(t *testing.T, mode LoadBalanceMode, rec *hostRecorder) *LoadBalancer {
+	t.Helper()
+	lb, err := NewLoadBalancer([]string{"http://a/key1", "http://b/key2", "https://c"}, mode)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lb.Base = rec
+	return lb
+}
+
+func rpcRequest(t *testing.T, lb *LoadBalancer, body string) int {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPost, "http://evm-load-balancer", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := lb.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("round trip: %v", err)
+	}
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+const (
+	readCall  = `{"jsonrpc":"2.0","id":1,"method":"eth_blockNumber"}`
+	writeCall = `{"jsonrpc":"2.0","id":1,"method":"eth_sendRawTransaction","params":["0x00"]}`
+)
+
+func TestLoadBalancerRoundRobin(t *testing.T) {
+	rec := &hostRecorder{}
+	lb := newTestBalancer(t, LoadBalanceRoundRobin, rec)
+
+	for i := 0; i < 6; i++ {
+		rpcRequest(t, lb, readCall)
+	}
+	counts := map[string]int{}
+	for _, h := range rec.take() {
+		counts[h]++
+	}
+	if counts["a"] != 2 || counts["b"] != 2 || counts["c"] != 2 {
+		t.Errorf("reads per endpoint = %v, want 2 each", counts)
+	}
+}
+
+func TestLoadBalancerPinsWrites(t *testing.T) {
+	rec := &hostRecorder{}
+	lb := newTestBalancer(t, LoadBalanceRoundRobin, rec)
+
+	batch := "[" + readCall + "," + writeCall + "]"
+	for _, body := range []string{writeCall, writeCall, batch} {
+		rpcRequest(t, lb, body)
+	}
+	for _, h := range rec.take() {
+		if h != "a" {
+			t.Errorf("write went to %s, want primary a", h)
+		}
+	}
+}
+
+func TestLoadBalancerSkipsUnhealthy(t *testing.T) {
+	rec := &hostRecorder{failing: map[string]bool{"b": true}}
+	lb := newTestBalancer(t, LoadBalanceRoundRobin, rec)
+	lb.Cooldown = time.Hour
+
+	// Every read succeeds: b's 503 is retried elsewhere.
+	for i := 0; i < 6; i++ {
+		if status := rpcRequest(t, lb, readCall); status != http.StatusOK {
+			t.Fatalf("read %d: status %d", i, status)
+		}
+	}
+	hosts := rec.take()
+	var bHits int
+	for _, h := range hosts {
+		if h == "b" {
+			bHits++
+		}
+	}
+	if bHits != 1 {
+		t.Errorf("b hit %d times (%v), want once before the cooldown", bHits, hosts)
+	}
+
+	// With the primary down, writes move to the next healthy endpoint.
+	lb.endpoints[0].unhealthyUntil.Store(time.Now().Add(time.Hour).UnixNano())
+	rpcRequest(t, lb, writeCall)
+	if hosts := rec.take(); len(hosts) != 1 || hosts[0] != "c" {
+		t.Errorf("write went to %v, want [c]", hosts)
+	}
+}
+
+func TestLoadBalancerLeastLoaded(t *testing.T) {
+	rec := &hostRecorder{}
+	lb := newTestBalancer(t, LoadBalanceLeastLoaded, rec)
+	lb.endpoints[0].inFlight.Add(5)
+	lb.endpoints[2].inFlight.Add(5)
+
+	for i := 0; i < 3; i++ {
+		rpcRequest(t, lb, readCall)
+	}
+	for _, h := range rec.take() {
+		if h != "b" {
+			t.Errorf("read went to %s, want least-loaded b", h)
+		}
+	}
+}
+
+func TestNewLoadBalancerRejectsBadURL(t *testing.T) {
+	if _, err := NewLoadBalancer(nil, LoadBalanceRoundRobin); err == nil {
+		t.Error("no endpoints: want error")
+	}
+	_, err := NewLoadBalancer([]string{"http://a", "ws://b/secret"}, LoadBalanceRoundRobin)
+	if err == nil || strings.Contains(err.Error(), "secret") {
+		t.Errorf("err = %v, want an error without the URL", err)
+	}
+}
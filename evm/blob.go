@@ -0,0 +1,103 @@
+package evm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/holiman/uint256"
+)
+
+// ErrBlobsUnsupported is returned for blob operations on a chain whose head has no
+// EIP-4844 fields (pre-Cancun, or an L2 without blobs).
+var ErrBlobsUnsupported = errors.New("evm: chain does not support blob transactions (no excessBlobGas)")
+
+// EIP-4844 / EIP-7691 blob base fee parameters, used when the node lacks eth_blobBaseFee.
+const (
+	minBlobBaseFee            = 1
+	blobBaseFeeFractionCancun = 3338477
+	blobBaseFeeFractionPrague = 5007716
+)
+
+type blobBaseFeeReader interface {
+	BlobBaseFee(ctx context.Context) (*big.Int, error)
+}
+
+// SuggestBlobFee returns the blob base fee of the next block. It asks the node
+// (eth_blobBaseFee) when the client supports it and otherwise computes it from the
+// head's excessBlobGas. The computed value assumes Cancun or Prague parameters (the
+// latter when the head carries a requests hash) and may be off on chains with their
+// own blob schedule.
+func SuggestBlobFee(ctx context.Context, client BlockchainClient) (*big.Int, error) {
+	head, err := client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("evm: read head: %w", err)
+	}
+	if head.ExcessBlobGas == nil {
+		return nil, ErrBlobsUnsupported
+	}
+
+	if r, ok := client.(blobBaseFeeReader); ok {
+		if fee, err := r.BlobBaseFee(ctx); err == nil {
+			return fee, nil
+		}
+	}
+
+	fraction := int64(blobBaseFeeFractionCancun)
+	if head.RequestsHash != nil {
+		fraction = blobBaseFeeFractionPrague
+	}
+	return fakeExponential(big.NewInt(minBlobBaseFee), new(big.Int).SetUint64(*head.ExcessBlobGas), big.NewInt(fraction)), nil
+}
+
+// fakeExponential approximates factor * e ** (numerator / denominator) (EIP-4844).
+func fakeExponential(factor, numerator, denominator *big.Int) *big.Int {
+	var (
+		output = new(big.Int)
+		accum  = new(big.Int).Mul(factor, denominator)
+	)
+	for i := 1; accum.Sign() > 0; i++ {
+		output.Add(output, accum)
+
+		accum.Mul(accum, numerator)
+		accum.Div(accum, denominator)
+		accum.Div(accum, big.NewInt(int64(i)))
+	}
+	return output.Div(output, denominator)
+}
+
+// blobTxData builds a type-3 transaction from the dynamic fees SendTx resolved.
+func blobTxData(ctx context.Context, client BlockchainClient, req TxRequest, chainID *big.Int, nonce uint64, fees *txFees, gasLimit uint64, value *big.Int) (*types.BlobTx, error) {
+	blobFeeCap, err := resolveBlobFeeCap(ctx, client, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.BlobTx{
+		ChainID:    uint256.MustFromBig(chainID),
+		Nonce:      nonce,
+		GasTipCap:  uint256.MustFromBig(fees.tipCap),
+		GasFeeCap:  uint256.MustFromBig(fees.feeCap),
+		Gas:        gasLimit,
+		To:         *req.To,
+		Value:      uint256.MustFromBig(value),
+		Data:       req.Data,
+		BlobFeeCap: uint256.MustFromBig(blobFeeCap),
+		BlobHashes: req.BlobSidecar.BlobHashes(),
+		Sidecar:    req.BlobSidecar,
+	}, nil
+}
+
+// resolveBlobFeeCap defaults to 2x the current blob base fee, like the execution fee cap.
+func resolveBlobFeeCap(ctx context.Context, client BlockchainClient, req TxRequest) (*big.Int, error) {
+	if req.MaxFeePerBlobGas != nil {
+		return req.MaxFeePerBlobGas, nil
+	}
+	fee, err := SuggestBlobFee(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).Mul(fee, big.NewInt(2)), nil
+}
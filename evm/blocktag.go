@@ -0,0 +1,53 @@
+package evm
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// BlockTag is a JSON-RPC block parameter: a named tag or a 0x-prefixed hex block number.
+type BlockTag string
+
+const (
+	BlockLatest    BlockTag = "latest"
+	BlockPending   BlockTag = "pending"
+	BlockEarliest  BlockTag = "earliest"
+	BlockSafe      BlockTag = "safe"      // post-merge: unlikely to reorg
+	BlockFinalized BlockTag = "finalized" // post-merge: will not reorg
+)
+
+// ValidateBlockTag accepts the named tags above and hex block numbers such as "0x1b4".
+func ValidateBlockTag(tag string) error {
+	_, err := BlockTag(tag).BlockNumber()
+	return err
+}
+
+// BlockNumber converts the tag to the *big.Int block argument BlockchainClient methods take:
+// nil for latest, a negative rpc.BlockNumber for the other named tags, the number itself otherwise.
+func (t BlockTag) BlockNumber() (*big.Int, error) {
+	switch t {
+	case BlockLatest:
+		return nil, nil
+	case BlockPending:
+		return big.NewInt(int64(rpc.PendingBlockNumber)), nil
+	case BlockEarliest:
+		return big.NewInt(int64(rpc.EarliestBlockNumber)), nil
+	case BlockSafe:
+		return big.NewInt(int64(rpc.SafeBlockNumber)), nil
+	case BlockFinalized:
+		return big.NewInt(int64(rpc.FinalizedBlockNumber)), nil
+	}
+
+	if !strings.HasPrefix(string(t), "0x") {
+		return nil, fmt.Errorf("evm: invalid block tag %q", string(t))
+	}
+	n, err := hexutil.DecodeBig(string(t))
+	if err != nil {
+		return nil, fmt.Errorf("evm: invalid block number %q: %w", string(t), err)
+	}
+	return n, nil
+}
@@ -0,0 +1,135 @@
+package evm
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/holiman/uint256"
+)
+
+// MinFeeBumpPercent is the smallest fee increase geth's txpool accepts for a replacement.
+const MinFeeBumpPercent = 10
+
+// maxFeeBumpAttempts bounds how often ResendWithHigherFee escalates after
+// ErrReplacementUnderpriced.
+const maxFeeBumpAttempts = 5
+
+// ResendWithHigherFee replaces originalTx with a copy that has the same nonce, gas,
+// recipient, value and data but fees raised by bumpPercent (at least MinFeeBumpPercent),
+// signs it with privKey and broadcasts it. Every fee field the type has (gas price, tip
+// and fee cap, blob fee cap) is bumped, since nodes require all of them to go up.
+//
+// If the node still answers ErrReplacementUnderpriced (e.g. the pending tx was itself
+// replaced), the bump grows by bumpPercent and the send is retried, up to
+// maxFeeBumpAttempts times. Blob transactions can only be resent when originalTx still
+// carries its sidecar. Set-code (EIP-7702) transactions are not supported.
+func ResendWithHigherFee(ctx context.Context, client BlockchainClient, originalTx *types.Transaction, privKey *ecdsa.PrivateKey, bumpPercent int) (common.Hash, error) {
+	if client == nil {
+		return common.Hash{}, errors.New("evm: nil client")
+	}
+	if originalTx == nil || privKey == nil {
+		return common.Hash{}, errors.New("evm: original tx and private key are required")
+	}
+	if bumpPercent < MinFeeBumpPercent {
+		bumpPercent = MinFeeBumpPercent
+	}
+
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("evm: chain id: %w", err)
+	}
+	signer := types.LatestSignerForChainID(chainID)
+
+	var lastErr error
+	for attempt := 1; attempt <= maxFeeBumpAttempts; attempt++ {
+		txData, err := bumpedTxData(originalTx, chainID, bumpPercent*attempt)
+		if err != nil {
+			return common.Hash{}, err
+		}
+		signed, err := types.SignTx(types.NewTx(txData), signer, privKey)
+		if err != nil {
+			return common.Hash{}, fmt.Errorf("evm: sign tx: %w", err)
+		}
+
+		err = ClassifySendError(client.SendTransaction(ctx, signed))
+		if err == nil {
+			return signed.Hash(), nil
+		}
+		if !errors.Is(err, ErrReplacementUnderpriced) {
+			return common.Hash{}, fmt.Errorf("evm: send tx: %w", err)
+		}
+		lastErr = err
+	}
+	return common.Hash{}, fmt.Errorf("evm: resend nonce %d: gave up after %d bumps: %w", originalTx.Nonce(), maxFeeBumpAttempts, lastErr)
+}
+
+func bumpedTxData(tx *types.Transaction, chainID *big.Int, percent int) (types.TxData, error) {
+	switch tx.Type() {
+	case types.LegacyTxType:
+		return &types.LegacyTx{
+			Nonce:    tx.Nonce(),
+			GasPrice: bumpFee(tx.GasPrice(), percent),
+			Gas:      tx.Gas(),
+			To:       tx.To(),
+			Value:    tx.Value(),
+			Data:     tx.Data(),
+		}, nil
+	case types.AccessListTxType:
+		return &types.AccessListTx{
+			ChainID:    chainID,
+			Nonce:      tx.Nonce(),
+			GasPrice:   bumpFee(tx.GasPrice(), percent),
+			Gas:        tx.Gas(),
+			To:         tx.To(),
+			Value:      tx.Value(),
+			Data:       tx.Data(),
+			AccessList: tx.AccessList(),
+		}, nil
+	case types.DynamicFeeTxType:
+		return &types.DynamicFeeTx{
+			ChainID:    chainID,
+			Nonce:      tx.Nonce(),
+			GasTipCap:  bumpFee(tx.GasTipCap(), percent),
+			GasFeeCap:  bumpFee(tx.GasFeeCap(), percent),
+			Gas:        tx.Gas(),
+			To:         tx.To(),
+			Value:      tx.Value(),
+			Data:       tx.Data(),
+			AccessList: tx.AccessList(),
+		}, nil
+	case types.BlobTxType:
+		sidecar := tx.BlobTxSidecar()
+		if sidecar == nil {
+			return nil, errors.New("evm: blob transaction has no sidecar to resend")
+		}
+		return &types.BlobTx{
+			ChainID:    uint256.MustFromBig(chainID),
+			Nonce:      tx.Nonce(),
+			GasTipCap:  uint256.MustFromBig(bumpFee(tx.GasTipCap(), percent)),
+			GasFeeCap:  uint256.MustFromBig(bumpFee(tx.GasFeeCap(), percent)),
+			Gas:        tx.Gas(),
+			To:         *tx.To(),
+			Value:      uint256.MustFromBig(tx.Value()),
+			Data:       tx.Data(),
+			AccessList: tx.AccessList(),
+			BlobFeeCap: uint256.MustFromBig(bumpFee(tx.BlobGasFeeCap(), percent)),
+			BlobHashes: tx.BlobHashes(),
+			Sidecar:    sidecar,
+		}, nil
+	default:
+		return nil, fmt.Errorf("evm: cannot bump fees of transaction type %d", tx.Type())
+	}
+}
+
+// bumpFee returns fee * (100+percent) / 100, rounded up so small fees still clear the
+// minimum bump.
+func bumpFee(fee *big.Int, percent int) *big.Int {
+	out := new(big.Int).Mul(fee, big.NewInt(int64(100+percent)))
+	out.Add(out, big.NewInt(99))
+	return out.Div(out, big.NewInt(100))
+}
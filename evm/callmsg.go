@@ -0,0 +1,89 @@
+package evm
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	ErrInvalidAddress  = errors.New("evm: invalid address")
+	ErrInvalidCallData = errors.New("evm: invalid call data")
+	ErrInvalidCallMsg  = errors.New("evm: invalid call message")
+)
+
+// ParseAddress parses a hex address strictly, for addresses coming from config or user
+// input. common.HexToAddress silently pads or truncates a typo'd string into some other
+// valid address; ParseAddress instead requires exactly 20 bytes of hex (0x prefix
+// optional) and, for mixed-case input, a valid EIP-55 checksum.
+func ParseAddress(s string) (common.Address, error) {
+	digits := trim0x(s)
+	if len(digits) != 2*common.AddressLength {
+		return common.Address{}, fmt.Errorf("%w %q: %d hex digits, want %d", ErrInvalidAddress, s, len(digits), 2*common.AddressLength)
+	}
+	b, err := hex.DecodeString(digits)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("%w %q: %v", ErrInvalidAddress, s, err)
+	}
+	addr := common.BytesToAddress(b)
+	if digits != strings.ToLower(digits) && digits != strings.ToUpper(digits) && addr.Hex()[2:] != digits {
+		return common.Address{}, fmt.Errorf("%w %q: bad EIP-55 checksum (want %s)", ErrInvalidAddress, s, addr.Hex())
+	}
+	return addr, nil
+}
+
+// ParseCallData decodes hex call data (0x prefix optional), rejecting odd lengths and
+// non-hex digits instead of letting the node fail the call.
+func ParseCallData(s string) ([]byte, error) {
+	digits := trim0x(s)
+	if len(digits)%2 != 0 {
+		return nil, fmt.Errorf("%w: odd number of hex digits (%d)", ErrInvalidCallData, len(digits))
+	}
+	b, err := hex.DecodeString(digits)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCallData, err)
+	}
+	return b, nil
+}
+
+// ValidateCallMsg rejects messages every node would refuse, so the mistake surfaces
+// locally with a clear error instead of as a node error after a round trip: negative
+// amounts, gasPrice combined with EIP-1559 fees, and a tip above the fee cap.
+func ValidateCallMsg(msg ethereum.CallMsg) error {
+	for _, f := range []struct {
+		name string
+		v    *big.Int
+	}{
+		{"value", msg.Value},
+		{"gasPrice", msg.GasPrice},
+		{"maxFeePerGas", msg.GasFeeCap},
+		{"maxPriorityFeePerGas", msg.GasTipCap},
+		{"maxFeePerBlobGas", msg.BlobGasFeeCap},
+	} {
+		if f.v != nil && f.v.Sign() < 0 {
+			return fmt.Errorf("%w: negative %s %s", ErrInvalidCallMsg, f.name, f.v)
+		}
+	}
+	if msg.GasPrice != nil && (msg.GasFeeCap != nil || msg.GasTipCap != nil) {
+		return fmt.Errorf("%w: both gasPrice and EIP-1559 fees set", ErrInvalidCallMsg)
+	}
+	if msg.GasFeeCap != nil && msg.GasTipCap != nil && msg.GasTipCap.Cmp(msg.GasFeeCap) > 0 {
+		return fmt.Errorf("%w: maxPriorityFeePerGas %s above maxFeePerGas %s", ErrInvalidCallMsg, msg.GasTipCap, msg.GasFeeCap)
+	}
+	if len(msg.BlobHashes) > 0 && msg.To == nil {
+		return fmt.Errorf("%w: blob transactions cannot create contracts", ErrInvalidCallMsg)
+	}
+	return nil
+}
+
+func trim0x(s string) string {
+	if len(s) >= 2 && s[0] == '0' && (s[1] == 'x' || s[1] == 'X') {
+		return s[2:]
+	}
+	return s
+}
@@ -0,0 +1,83 @@
+package evm
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestParseAddress(t *testing.T) {
+	tests := []struct {
+		in string
+		ok bool
+	}{
+		// EIP-55 examples.
+		{"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", true},
+		{"0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359", true},
+		{"0xdbF03B407c01E7cD3CBea99509d93f8DDDC8C6FB", true},
+		{"0xD1220A0cf47c7B9Be7A2E6BA89F429762e7b9aDb", true},
+		{"5aaeb6053f3e94c9b9a09f33669435e7ef1beaed", true},
+		{"0x5AAEB6053F3E94C9B9A09F33669435E7EF1BEAED", true},
+		{"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD", false}, // checksum
+		{"0x5aaeb6053f3e94c9b9a09f33669435e7ef1beae", false},  // 19.5 bytes
+		{"0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed00", false},
+		{"0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaeg", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		addr, err := ParseAddress(tt.in)
+		if tt.ok {
+			if err != nil {
+				t.Errorf("ParseAddress(%q): %v", tt.in, err)
+			} else if addr != common.HexToAddress(tt.in) {
+				t.Errorf("ParseAddress(%q) = %s", tt.in, addr.Hex())
+			}
+			continue
+		}
+		if !errors.Is(err, ErrInvalidAddress) {
+			t.Errorf("ParseAddress(%q) err = %v, want ErrInvalidAddress", tt.in, err)
+		}
+	}
+}
+
+func TestParseCallData(t *testing.T) {
+	if b, err := ParseCallData("0xa9059cbb"); err != nil || len(b) != 4 {
+		t.Errorf("ParseCallData(selector) = %x, %v", b, err)
+	}
+	if b, err := ParseCallData("0x"); err != nil || len(b) != 0 {
+		t.Errorf("ParseCallData(0x) = %x, %v", b, err)
+	}
+	for _, in := range []string{"0xa9059cb", "0xzz"} {
+		if _, err := ParseCallData(in); !errors.Is(err, ErrInvalidCallData) {
+			t.Errorf("ParseCallData(%q) err = %v, want ErrInvalidCallData", in, err)
+		}
+	}
+}
+
+func TestValidateCallMsg(t *testing.T) {
+	to := common.HexToAddress("0x01")
+	tests := []struct {
+		name string
+		msg  ethereum.CallMsg
+		ok   bool
+	}{
+		{"plain", ethereum.CallMsg{To: &to, Value: big.NewInt(1)}, true},
+		{"1559", ethereum.CallMsg{To: &to, GasFeeCap: big.NewInt(10), GasTipCap: big.NewInt(2)}, true},
+		{"negative value", ethereum.CallMsg{To: &to, Value: big.NewInt(-1)}, false},
+		{"mixed fees", ethereum.CallMsg{To: &to, GasPrice: big.NewInt(1), GasFeeCap: big.NewInt(1)}, false},
+		{"tip above cap", ethereum.CallMsg{To: &to, GasFeeCap: big.NewInt(1), GasTipCap: big.NewInt(2)}, false},
+		{"blob create", ethereum.CallMsg{BlobHashes: []common.Hash{{1}}}, false},
+	}
+	for _, tt := range tests {
+		err := ValidateCallMsg(tt.msg)
+		if tt.ok && err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+		}
+		if !tt.ok && !errors.Is(err, ErrInvalidCallMsg) {
+			t.Errorf("%s: err = %v, want ErrInvalidCallMsg", tt.name, err)
+		}
+	}
+}
@@ -2,13 +2,20 @@ package evm
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"math/big"
+	"net/http"
+	"strings"
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
 )
 
 type BlockchainClient interface {
@@ -37,10 +44,37 @@ type BlockchainClient interface {
 	CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error)
 }
 
+// SyncStatus is the eth_syncing progress of a node that is still catching up.
+type SyncStatus struct {
+	StartingBlock uint64
+	CurrentBlock  uint64
+	HighestBlock  uint64
+}
+
+// Lag is how many blocks the node is behind the highest block it knows of.
+func (s *SyncStatus) Lag() uint64 {
+	if s == nil || s.HighestBlock <= s.CurrentBlock {
+		return 0
+	}
+	return s.HighestBlock - s.CurrentBlock
+}
+
+// BlockTransaction is a transaction together with its position in the block.
+type BlockTransaction struct {
+	Tx          *types.Transaction
+	From        common.Address
+	BlockHash   common.Hash
+	BlockNumber uint64
+	Index       uint
+}
+
 // LiveBlockchainClient production implementation.
 // wraps a real RPC-backed ethclient.Client (HTTP/WS).
 type LiveBlockchainClient struct {
 	*ethclient.Client
+
+	network  string
+	endpoint string
 }
 
 var _ BlockchainClient = (*LiveBlockchainClient)(nil)
@@ -48,3 +82,218 @@ var _ BlockchainClient = (*LiveBlockchainClient)(nil)
 func NewLiveBlockchainClient(c *ethclient.Client) *LiveBlockchainClient {
 	return &LiveBlockchainClient{Client: c}
 }
+
+// WithLabels returns a client sharing c's connection whose *RPCError values carry the
+// given network and endpoint names. Use a name for the endpoint ("alchemy", "node-2"),
+// not its URL. Closing either client closes both.
+func (c *LiveBlockchainClient) WithLabels(network, endpoint string) *LiveBlockchainClient {
+	return &LiveBlockchainClient{Client: c.Client, network: network, endpoint: endpoint}
+}
+
+// Clone returns a client sharing c's connection and labels, for callers that want
+// their own value to relabel. Closing either client closes both.
+func (c *LiveBlockchainClient) Clone() *LiveBlockchainClient {
+	return c.WithLabels(c.network, c.endpoint)
+}
+
+// WrapRPCError classifies err from a call of method on c and annotates it as an
+// *RPCError with c's labels, e.g. for errors from the embedded ethclient methods.
+// It returns nil for a nil err.
+func (c *LiveBlockchainClient) WrapRPCError(ctx context.Context, method string, err error) error {
+	return newRPCError(ctx, method, c.network, c.endpoint, err)
+}
+
+// DialLiveBlockchainClient dials endpoint: http(s):// and ws(s):// URLs, or an IPC socket
+// given as a path ("/path/to/geth.ipc") or as "ipc:///path/to/geth.ipc". IPC suits a
+// co-located node: no HTTP overhead and nothing exposed on the network.
+func DialLiveBlockchainClient(ctx context.Context, endpoint string) (*LiveBlockchainClient, error) {
+	if path, ok := strings.CutPrefix(endpoint, "ipc://"); ok {
+		endpoint = path
+	}
+	rpcClient, err := rpc.DialContext(ctx, endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("evm: dial %s: %w", endpoint, err)
+	}
+	return NewLiveBlockchainClient(ethclient.NewClient(rpcClient)), nil
+}
+
+// NewLiveBlockchainClientWithTransport dials an HTTP endpoint with rt as the HTTP
+// transport, so tests can serve canned JSON-RPC responses (e.g. keyed by method)
+// without a real node.
+func NewLiveBlockchainClientWithTransport(ctx context.Context, url string, rt http.RoundTripper) (*LiveBlockchainClient, error) {
+	rpcClient, err := rpc.DialOptions(ctx, url, rpc.WithHTTPClient(&http.Client{Transport: rt}))
+	if err != nil {
+		return nil, fmt.Errorf("evm: dial %s: %w", url, err)
+	}
+	return NewLiveBlockchainClient(ethclient.NewClient(rpcClient)), nil
+}
+
+// Syncing calls eth_syncing. It returns nil when the node is fully synced.
+func (c *LiveBlockchainClient) Syncing(ctx context.Context) (*SyncStatus, error) {
+	progress, err := c.Client.SyncProgress(ctx)
+	if err != nil {
+		return nil, c.WrapRPCError(ctx, "eth_syncing", err)
+	}
+	if progress == nil {
+		return nil, nil
+	}
+	return &SyncStatus{
+		StartingBlock: progress.StartingBlock,
+		CurrentBlock:  progress.CurrentBlock,
+		HighestBlock:  progress.HighestBlock,
+	}, nil
+}
+
+// GetBalances fetches all balances in a single JSON-RPC batch of eth_getBalance calls.
+// Per-address failures are joined into the returned error alongside the partial map.
+func (c *LiveBlockchainClient) GetBalances(ctx context.Context, addresses []common.Address, blockTag BlockTag) (map[common.Address]*big.Int, error) {
+	number, err := blockTag.BlockNumber()
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[common.Address]*big.Int, len(addresses))
+	if len(addresses) == 0 {
+		return out, nil
+	}
+
+	tag := toBlockNumArg(number)
+	batch := make([]rpc.BatchElem, len(addresses))
+	for i, addr := range addresses {
+		batch[i] = rpc.BatchElem{
+			Method: "eth_getBalance",
+			Args:   []interface{}{addr, tag},
+			Result: new(hexutil.Big),
+		}
+	}
+
+	if err := c.Client.Client().BatchCallContext(ctx, batch); err != nil {
+		return nil, c.WrapRPCError(ctx, "eth_getBalance", err)
+	}
+
+	var errs []error
+	for i, elem := range batch {
+		if elem.Error != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", addresses[i].Hex(), c.WrapRPCError(ctx, "eth_getBalance", elem.Error)))
+			continue
+		}
+		out[addresses[i]] = elem.Result.(*hexutil.Big).ToInt()
+	}
+	return out, errors.Join(errs...)
+}
+
+// GetTransactionByBlockAndIndex calls eth_getTransactionByBlockNumberAndIndex, so a worker
+// can fetch the Nth transaction without downloading the whole block.
+// Returns ethereum.NotFound if the block or index doesn't exist.
+func (c *LiveBlockchainClient) GetTransactionByBlockAndIndex(ctx context.Context, blockTag BlockTag, index uint) (*BlockTransaction, error) {
+	number, err := blockTag.BlockNumber()
+	if err != nil {
+		return nil, err
+	}
+
+	var raw json.RawMessage
+	if err := c.Client.Client().CallContext(ctx, &raw, "eth_getTransactionByBlockNumberAndIndex",
+		toBlockNumArg(number), hexutil.Uint(index)); err != nil {
+		return nil, c.WrapRPCError(ctx, "eth_getTransactionByBlockNumberAndIndex", err)
+	}
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, ethereum.NotFound
+	}
+
+	tx := new(types.Transaction)
+	if err := json.Unmarshal(raw, tx); err != nil {
+		return nil, fmt.Errorf("evm: decode transaction: %w", err)
+	}
+	var meta struct {
+		From             common.Address `json:"from"`
+		BlockHash        common.Hash    `json:"blockHash"`
+		BlockNumber      hexutil.Uint64 `json:"blockNumber"`
+		TransactionIndex hexutil.Uint   `json:"transactionIndex"`
+	}
+	if err := json.Unmarshal(raw, &meta); err != nil {
+		return nil, fmt.Errorf("evm: decode transaction position: %w", err)
+	}
+
+	return &BlockTransaction{
+		Tx:          tx,
+		From:        meta.From,
+		BlockHash:   meta.BlockHash,
+		BlockNumber: uint64(meta.BlockNumber),
+		Index:       uint(meta.TransactionIndex),
+	}, nil
+}
+
+// BlockTransactionCount calls eth_getBlockTransactionCountByNumber.
+// Returns ethereum.NotFound if the block doesn't exist.
+func (c *LiveBlockchainClient) BlockTransactionCount(ctx context.Context, blockTag BlockTag) (uint, error) {
+	number, err := blockTag.BlockNumber()
+	if err != nil {
+		return 0, err
+	}
+
+	var count *hexutil.Uint
+	if err := c.Client.Client().CallContext(ctx, &count, "eth_getBlockTransactionCountByNumber",
+		toBlockNumArg(number)); err != nil {
+		return 0, c.WrapRPCError(ctx, "eth_getBlockTransactionCountByNumber", err)
+	}
+	if count == nil {
+		return 0, ethereum.NotFound
+	}
+	return uint(*count), nil
+}
+
+// UncleCount calls eth_getUncleCountByBlockNumber. Post-merge blocks have no uncles.
+// Returns ethereum.NotFound if the block doesn't exist.
+func (c *LiveBlockchainClient) UncleCount(ctx context.Context, blockTag BlockTag) (uint, error) {
+	number, err := blockTag.BlockNumber()
+	if err != nil {
+		return 0, err
+	}
+
+	var count *hexutil.Uint
+	if err := c.Client.Client().CallContext(ctx, &count, "eth_getUncleCountByBlockNumber",
+		toBlockNumArg(number)); err != nil {
+		return 0, c.WrapRPCError(ctx, "eth_getUncleCountByBlockNumber", err)
+	}
+	if count == nil {
+		return 0, ethereum.NotFound
+	}
+	return uint(*count), nil
+}
+
+// GetUncleByBlockAndIndex calls eth_getUncleByBlockNumberAndIndex and returns the
+// uncle's header, or nil if the block or index doesn't exist.
+func (c *LiveBlockchainClient) GetUncleByBlockAndIndex(ctx context.Context, blockTag BlockTag, index uint) (*types.Header, error) {
+	number, err := blockTag.BlockNumber()
+	if err != nil {
+		return nil, err
+	}
+
+	var raw json.RawMessage
+	if err := c.Client.Client().CallContext(ctx, &raw, "eth_getUncleByBlockNumberAndIndex",
+		toBlockNumArg(number), hexutil.Uint(index)); err != nil {
+		return nil, c.WrapRPCError(ctx, "eth_getUncleByBlockNumberAndIndex", err)
+	}
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+
+	header := new(types.Header)
+	if err := json.Unmarshal(raw, header); err != nil {
+		return nil, fmt.Errorf("evm: decode uncle header: %w", err)
+	}
+	return header, nil
+}
+
+// toBlockNumArg mirrors ethclient's block argument encoding (nil = latest, negative = named tag).
+func toBlockNumArg(number *big.Int) string {
+	if number == nil {
+		return "latest"
+	}
+	if number.Sign() >= 0 {
+		return hexutil.EncodeBig(number)
+	}
+	if number.IsInt64() {
+		return rpc.BlockNumber(number.Int64()).String()
+	}
+	return fmt.Sprintf("<invalid %d>", number)
+}
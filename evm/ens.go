@@ -0,0 +1,127 @@
+package evm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// MainnetENSRegistry is the ENS registry deployed on Ethereum mainnet (and Sepolia/Holesky).
+var MainnetENSRegistry = common.HexToAddress("0x00000000000C2E074eC69A0dFb2997BA6C7d2e1e")
+
+var (
+	ErrENSNotConfigured = errors.New("evm: ENS registry not configured for this network")
+	ErrENSNotFound      = errors.New("evm: ENS name has no resolver or record")
+)
+
+var (
+	ensResolverSelector = crypto.Keccak256([]byte("resolver(bytes32)"))[:4]
+	ensAddrSelector     = crypto.Keccak256([]byte("addr(bytes32)"))[:4]
+	ensNameSelector     = crypto.Keccak256([]byte("name(bytes32)"))[:4]
+)
+
+// ENSNamehash implements the EIP-137 namehash. Labels are lowercased; full ENSIP-15
+// normalization (unicode, emoji) is the caller's job.
+func ENSNamehash(name string) common.Hash {
+	var node common.Hash
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return node
+	}
+	labels := strings.Split(name, ".")
+	for i := len(labels) - 1; i >= 0; i-- {
+		labelHash := crypto.Keccak256([]byte(labels[i]))
+		node = crypto.Keccak256Hash(node.Bytes(), labelHash)
+	}
+	return node
+}
+
+// ResolveENS resolves name to an address through the registry's resolver.
+// Pass the zero address as registry on networks without ENS to get ErrENSNotConfigured.
+func ResolveENS(ctx context.Context, client BlockchainClient, registry common.Address, name string) (common.Address, error) {
+	node := ENSNamehash(name)
+	resolver, err := ensResolver(ctx, client, registry, node)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("%w: %s", err, name)
+	}
+
+	out, err := ensCall(ctx, client, resolver, ensAddrSelector, node)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("evm: ENS addr(%s): %w", name, err)
+	}
+	if len(out) < 32 {
+		return common.Address{}, fmt.Errorf("%w: %s", ErrENSNotFound, name)
+	}
+	addr := common.BytesToAddress(out[:32])
+	if addr == (common.Address{}) {
+		return common.Address{}, fmt.Errorf("%w: %s", ErrENSNotFound, name)
+	}
+	return addr, nil
+}
+
+// ReverseResolveENS returns the primary name of addr from its <addr>.addr.reverse record.
+// The name is only returned if it forward-resolves back to addr, since anyone can set
+// a reverse record claiming any name.
+func ReverseResolveENS(ctx context.Context, client BlockchainClient, registry common.Address, addr common.Address) (string, error) {
+	reverse := strings.ToLower(strings.TrimPrefix(addr.Hex(), "0x")) + ".addr.reverse"
+	node := ENSNamehash(reverse)
+
+	resolver, err := ensResolver(ctx, client, registry, node)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", err, reverse)
+	}
+
+	out, err := ensCall(ctx, client, resolver, ensNameSelector, node)
+	if err != nil {
+		return "", fmt.Errorf("evm: ENS name(%s): %w", reverse, err)
+	}
+	stringType, _ := abi.NewType("string", "", nil)
+	vals, err := abi.Arguments{{Type: stringType}}.Unpack(out)
+	if err != nil || len(vals) != 1 {
+		return "", fmt.Errorf("%w: %s", ErrENSNotFound, reverse)
+	}
+	name, _ := vals[0].(string)
+	if name == "" {
+		return "", fmt.Errorf("%w: %s", ErrENSNotFound, reverse)
+	}
+
+	forward, err := ResolveENS(ctx, client, registry, name)
+	if err != nil {
+		return "", err
+	}
+	if forward != addr {
+		return "", fmt.Errorf("evm: ENS reverse record %s for %s resolves to %s", name, addr.Hex(), forward.Hex())
+	}
+	return name, nil
+}
+
+func ensResolver(ctx context.Context, client BlockchainClient, registry common.Address, node common.Hash) (common.Address, error) {
+	if registry == (common.Address{}) {
+		return common.Address{}, ErrENSNotConfigured
+	}
+	out, err := ensCall(ctx, client, registry, ensResolverSelector, node)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("evm: ENS resolver lookup: %w", err)
+	}
+	if len(out) < 32 {
+		return common.Address{}, ErrENSNotFound
+	}
+	resolver := common.BytesToAddress(out[:32])
+	if resolver == (common.Address{}) {
+		return common.Address{}, ErrENSNotFound
+	}
+	return resolver, nil
+}
+
+func ensCall(ctx context.Context, client BlockchainClient, to common.Address, selector []byte, node common.Hash) ([]byte, error) {
+	data := make([]byte, 0, len(selector)+common.HashLength)
+	data = append(data, selector...)
+	data = append(data, node.Bytes()...)
+	return client.CallContract(ctx, ethereum.CallMsg{To: &to, Data: data}, nil)
+}
@@ -0,0 +1,87 @@
+package evm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// DefaultGasBufferPercent is the headroom EstimateGasSafe adds on top of the node's estimate.
+const DefaultGasBufferPercent = 20
+
+// RevertError is returned when gas estimation fails because the call reverts.
+// Reason is the decoded Error(string) message when the contract provided one.
+type RevertError struct {
+	Reason string
+	Data   []byte
+	Err    error
+}
+
+func (e *RevertError) Error() string {
+	if e.Reason != "" {
+		return "evm: execution reverted: " + e.Reason
+	}
+	if len(e.Data) > 0 {
+		return "evm: execution reverted: " + hexutil.Encode(e.Data)
+	}
+	return "evm: execution reverted"
+}
+
+func (e *RevertError) Unwrap() error { return e.Err }
+
+// EstimateGasSafe is EstimateGasWithBuffer with DefaultGasBufferPercent.
+func EstimateGasSafe(ctx context.Context, client BlockchainClient, msg ethereum.CallMsg) (uint64, error) {
+	return EstimateGasWithBuffer(ctx, client, msg, DefaultGasBufferPercent)
+}
+
+// EstimateGasWithBuffer estimates gas for msg and adds bufferPercent on top.
+// If estimation fails, the call is replayed with eth_call to recover the revert
+// reason, which is returned as a *RevertError. msg is checked with ValidateCallMsg first.
+func EstimateGasWithBuffer(ctx context.Context, client BlockchainClient, msg ethereum.CallMsg, bufferPercent uint64) (uint64, error) {
+	if err := ValidateCallMsg(msg); err != nil {
+		return 0, err
+	}
+	gas, err := client.EstimateGas(ctx, msg)
+	if err == nil {
+		return gas + gas*bufferPercent/100, nil
+	}
+
+	if revertErr := revertErrorFrom(err); revertErr != nil {
+		return 0, revertErr
+	}
+
+	_, callErr := client.CallContract(ctx, msg, nil)
+	if callErr != nil {
+		if revertErr := revertErrorFrom(callErr); revertErr != nil {
+			return 0, revertErr
+		}
+	}
+	return 0, fmt.Errorf("evm: estimate gas: %w", err)
+}
+
+// revertErrorFrom extracts revert data from an RPC error, or returns nil if err isn't a revert.
+func revertErrorFrom(err error) *RevertError {
+	var dataErr rpc.DataError
+	if errors.As(err, &dataErr) {
+		if s, ok := dataErr.ErrorData().(string); ok {
+			if data, decErr := hexutil.Decode(s); decErr == nil {
+				out := &RevertError{Data: data, Err: err}
+				if reason, unpackErr := abi.UnpackRevert(data); unpackErr == nil {
+					out.Reason = reason
+				}
+				return out
+			}
+		}
+	}
+
+	if strings.Contains(strings.ToLower(err.Error()), "execution reverted") {
+		return &RevertError{Err: err}
+	}
+	return nil
+}
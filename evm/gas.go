@@ -0,0 +1,122 @@
+package evm
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+)
+
+// GasInfo is a snapshot of the chain's current fee parameters.
+// BaseFee and SuggestedTip are nil when the chain is not EIP-1559 enabled.
+type GasInfo struct {
+	BlockNumber       *big.Int
+	BaseFee           *big.Int
+	SuggestedTip      *big.Int
+	SuggestedGasPrice *big.Int
+	EIP1559           bool
+}
+
+// GetGasInfo assembles the latest base fee, eth_maxPriorityFeePerGas and eth_gasPrice into one struct.
+func GetGasInfo(ctx context.Context, client BlockchainClient) (*GasInfo, error) {
+	head, err := client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("evm: read head: %w", err)
+	}
+
+	price, err := client.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("evm: suggest gas price: %w", err)
+	}
+
+	info := &GasInfo{
+		BlockNumber:       head.Number,
+		SuggestedGasPrice: price,
+		EIP1559:           head.BaseFee != nil,
+	}
+	if !info.EIP1559 {
+		return info, nil
+	}
+
+	tip, err := client.SuggestGasTipCap(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("evm: suggest gas tip cap: %w", err)
+	}
+	info.BaseFee = new(big.Int).Set(head.BaseFee)
+	info.SuggestedTip = tip
+	return info, nil
+}
+
+const (
+	inclusionLookbackBlocks = 20
+	inclusionMaxBlocks      = 50
+
+	// EIP-1559 lets the base fee fall by at most 1/8 per block.
+	baseFeeMaxDecrease = 0.875
+)
+
+var inclusionPercentiles = []float64{10, 50, 90}
+
+// EstimateInclusion is a rough "~N blocks" estimate for a tx paying at most maxFeePerGas,
+// from the last 20 blocks of eth_feeHistory.
+//
+// The tip left after the next base fee is compared with each block's 10th/50th/90th
+// percentile rewards to get a per-block inclusion probability p; blocks is 1/p rounded
+// up and confidence is the chance of inclusion within that many blocks. If maxFeePerGas
+// is below the next base fee, the blocks needed for the base fee to fall that far are
+// added and confidence is halved, since a falling base fee is not a given.
+// blocks is -1 (confidence 0) when recent blocks suggest the tx won't be included.
+func EstimateInclusion(ctx context.Context, client BlockchainClient, maxFeePerGas *big.Int) (int, float64, error) {
+	if maxFeePerGas == nil || maxFeePerGas.Sign() <= 0 {
+		return 0, 0, fmt.Errorf("evm: maxFeePerGas must be positive")
+	}
+
+	hist, err := client.FeeHistory(ctx, inclusionLookbackBlocks, nil, inclusionPercentiles)
+	if err != nil {
+		return 0, 0, fmt.Errorf("evm: fee history: %w", err)
+	}
+	if len(hist.BaseFee) == 0 || len(hist.Reward) == 0 {
+		return 0, 0, fmt.Errorf("evm: fee history is empty")
+	}
+	// BaseFee has one more entry than Reward: the base fee of the next block.
+	nextBaseFee := hist.BaseFee[len(hist.BaseFee)-1]
+	if nextBaseFee == nil || nextBaseFee.Sign() == 0 {
+		return 0, 0, fmt.Errorf("evm: chain does not report an EIP-1559 base fee")
+	}
+
+	waitBlocks := 0
+	tip := new(big.Int).Sub(maxFeePerGas, nextBaseFee)
+	if tip.Sign() < 0 {
+		ratio, _ := new(big.Float).Quo(new(big.Float).SetInt(maxFeePerGas), new(big.Float).SetInt(nextBaseFee)).Float64()
+		waitBlocks = int(math.Ceil(math.Log(ratio) / math.Log(baseFeeMaxDecrease)))
+		tip.SetInt64(0)
+	}
+
+	var hits, total int
+	for _, rewards := range hist.Reward {
+		for _, r := range rewards {
+			if r == nil {
+				continue
+			}
+			total++
+			if tip.Cmp(r) >= 0 {
+				hits++
+			}
+		}
+	}
+	if total == 0 || hits == 0 {
+		return -1, 0, nil
+	}
+
+	p := float64(hits) / float64(total)
+	blocks := int(math.Ceil(1 / p))
+	confidence := 1 - math.Pow(1-p, float64(blocks))
+	if waitBlocks > 0 {
+		blocks += waitBlocks
+		confidence /= 2
+	}
+	if blocks > inclusionMaxBlocks {
+		return -1, 0, nil
+	}
+	return blocks, confidence, nil
+}
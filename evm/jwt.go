@@ -0,0 +1,22 @@
+package evm
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// NewLiveBlockchainClientWithJWT dials an endpoint that requires Engine-API-style JWT
+// auth (HS256 over a shared 32-byte secret). A fresh token with the current iat is
+// attached to every HTTP request (and to the websocket handshake), since nodes reject
+// tokens whose iat is more than a few seconds off.
+func NewLiveBlockchainClientWithJWT(ctx context.Context, url string, secret [32]byte) (*LiveBlockchainClient, error) {
+	rpcClient, err := rpc.DialOptions(ctx, url, rpc.WithHTTPAuth(node.NewJWTAuth(secret)))
+	if err != nil {
+		return nil, fmt.Errorf("evm: dial %s: %w", url, err)
+	}
+	return NewLiveBlockchainClient(ethclient.NewClient(rpcClient)), nil
+}
@@ -0,0 +1,130 @@
+package evm
+
+import (
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/pbkdf2"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"golang.org/x/text/unicode/norm"
+)
+
+// DefaultTestMnemonic is the mnemonic Hardhat and Anvil derive their default accounts from.
+const DefaultTestMnemonic = "test test test test test test test test test test test junk"
+
+// ErrInvalidDerivedKey is returned in the (astronomically unlikely) case that BIP-32
+// derivation lands on an invalid key.
+var ErrInvalidDerivedKey = errors.New("evm: derived key is invalid")
+
+// NewSimulatedBlockchainClientWithMnemonic derives count accounts from mnemonic along
+// m/44'/60'/0'/0/i, funds each with balanceWei in genesis, and returns them in index
+// order. With DefaultTestMnemonic the addresses match Hardhat/Anvil's default accounts,
+// so they are stable across runs.
+//
+// The mnemonic is not checked against the BIP-39 wordlist; any phrase works as a seed.
+func NewSimulatedBlockchainClientWithMnemonic(mnemonic string, count int, balanceWei *big.Int, opts SimOptions) (*SimulatedBlockchainClient, []*ecdsa.PrivateKey, []common.Address, error) {
+	if count <= 0 {
+		return nil, nil, nil, fmt.Errorf("evm: account count must be positive, got %d", count)
+	}
+	if balanceWei == nil {
+		balanceWei = new(big.Int)
+		balanceWei.SetString("10000000000000000000000", 10) // 10000 ETH, as Hardhat/Anvil
+	}
+
+	keys, err := DeriveMnemonicKeys(mnemonic, "", count)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	addrs := make([]common.Address, count)
+	alloc := make(types.GenesisAlloc, count)
+	for i, k := range keys {
+		addrs[i] = crypto.PubkeyToAddress(k.PublicKey)
+		alloc[addrs[i]] = types.Account{Balance: new(big.Int).Set(balanceWei)}
+	}
+	return NewSimulatedBlockchainClient(alloc, opts), keys, addrs, nil
+}
+
+// DeriveMnemonicKeys returns the first count keys on the BIP-44 Ethereum path
+// m/44'/60'/0'/0/i for the BIP-39 mnemonic and optional passphrase. Both are NFKD
+// normalized as BIP-39 requires, so non-English mnemonics derive the same keys as
+// other wallets.
+func DeriveMnemonicKeys(mnemonic, passphrase string, count int) ([]*ecdsa.PrivateKey, error) {
+	mnemonic = strings.Join(strings.Fields(norm.NFKD.String(mnemonic)), " ")
+	if mnemonic == "" {
+		return nil, fmt.Errorf("evm: mnemonic is empty")
+	}
+	salt := "mnemonic" + norm.NFKD.String(passphrase)
+	seed, err := pbkdf2.Key(sha512.New, mnemonic, []byte(salt), 2048, 64)
+	if err != nil {
+		return nil, fmt.Errorf("evm: mnemonic seed: %w", err)
+	}
+
+	mac := hmac.New(sha512.New, []byte("Bitcoin seed"))
+	mac.Write(seed)
+	sum := mac.Sum(nil)
+	key, chain := new(big.Int).SetBytes(sum[:32]), sum[32:]
+	if key.Sign() == 0 || key.Cmp(crypto.S256().Params().N) >= 0 {
+		return nil, ErrInvalidDerivedKey
+	}
+
+	// Derive the shared m/44'/60'/0'/0 parent once, then each leaf from it.
+	base := accounts.DefaultBaseDerivationPath
+	for _, index := range base[:len(base)-1] {
+		if key, chain, err = deriveChild(key, chain, index); err != nil {
+			return nil, err
+		}
+	}
+
+	keys := make([]*ecdsa.PrivateKey, count)
+	for i := range keys {
+		k, _, err := deriveChild(key, chain, uint32(i))
+		if err != nil {
+			return nil, err
+		}
+		if keys[i], err = crypto.ToECDSA(k.FillBytes(make([]byte, 32))); err != nil {
+			return nil, fmt.Errorf("evm: derived key %d: %w", i, err)
+		}
+	}
+	return keys, nil
+}
+
+// deriveChild is BIP-32 CKDpriv.
+func deriveChild(key *big.Int, chain []byte, index uint32) (*big.Int, []byte, error) {
+	var data []byte
+	if index >= 0x80000000 {
+		data = append([]byte{0}, key.FillBytes(make([]byte, 32))...)
+	} else {
+		priv, err := crypto.ToECDSA(key.FillBytes(make([]byte, 32)))
+		if err != nil {
+			return nil, nil, err
+		}
+		data = crypto.CompressPubkey(&priv.PublicKey)
+	}
+	data = binary.BigEndian.AppendUint32(data, index)
+
+	mac := hmac.New(sha512.New, chain)
+	mac.Write(data)
+	sum := mac.Sum(nil)
+
+	n := crypto.S256().Params().N
+	il := new(big.Int).SetBytes(sum[:32])
+	if il.Cmp(n) >= 0 {
+		return nil, nil, ErrInvalidDerivedKey
+	}
+	child := il.Add(il, key)
+	child.Mod(child, n)
+	if child.Sign() == 0 {
+		return nil, nil, ErrInvalidDerivedKey
+	}
+	return child, sum[32:], nil
+}
@@ -0,0 +1,48 @@
+package evm
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Hardhat and Anvil's default accounts.
+func TestDeriveMnemonicKeysDefaultTestMnemonic(t *testing.T) {
+	want := []string{
+		"0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266",
+		"0x70997970C51812dc3A010C7d01b50e0d17dc79C8",
+	}
+	keys, err := DeriveMnemonicKeys(DefaultTestMnemonic, "", len(want))
+	if err != nil {
+		t.Fatalf("DeriveMnemonicKeys: %v", err)
+	}
+	for i, k := range keys {
+		if got := crypto.PubkeyToAddress(k.PublicKey); got != common.HexToAddress(want[i]) {
+			t.Errorf("index %d: got %s, want %s", i, got.Hex(), want[i])
+		}
+	}
+}
+
+func TestDeriveMnemonicKeysNormalizesInput(t *testing.T) {
+	// "é" precomposed (NFC) and as e + combining acute (NFD) must derive the same key.
+	composed, err := DeriveMnemonicKeys("café "+DefaultTestMnemonic, "passé", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decomposed, err := DeriveMnemonicKeys("café  "+DefaultTestMnemonic, "passé", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !composed[0].Equal(decomposed[0]) {
+		t.Fatal("NFC and NFD spellings of the same mnemonic derived different keys")
+	}
+
+	plain, err := DeriveMnemonicKeys(DefaultTestMnemonic, "passé", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plain[0].Equal(composed[0]) {
+		t.Fatal("different mnemonics derived the same key")
+	}
+}
@@ -0,0 +1,158 @@
+package evm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ErrChainIDMismatch is returned by DialNetwork when the endpoint serves another chain
+// than the network it was configured for.
+var ErrChainIDMismatch = errors.New("evm: endpoint chain id does not match the network")
+
+// ErrNetworkClientsClosed is returned by NetworkClients.Client after Close.
+var ErrNetworkClientsClosed = errors.New("evm: network clients closed")
+
+// Network is a preset for a public EVM network.
+type Network struct {
+	ChainID uint64
+	// RPCURL is a free public endpoint: rate-limited, fine for bootstrapping and tests.
+	// Production deployments should pass their provider's URL to DialNetwork instead.
+	RPCURL string
+	// ENSRegistry is the zero address where ENS isn't deployed (ResolveENS then
+	// returns ErrENSNotConfigured).
+	ENSRegistry common.Address
+}
+
+// DefaultNetworks returns presets for common EVM networks keyed by name. The map is a
+// fresh copy on every call, so callers may edit it.
+func DefaultNetworks() map[string]Network {
+	return map[string]Network{
+		"mainnet":      {ChainID: 1, RPCURL: "https://ethereum-rpc.publicnode.com", ENSRegistry: MainnetENSRegistry},
+		"sepolia":      {ChainID: 11155111, RPCURL: "https://ethereum-sepolia-rpc.publicnode.com", ENSRegistry: MainnetENSRegistry},
+		"hoodi":        {ChainID: 560048, RPCURL: "https://ethereum-hoodi-rpc.publicnode.com"},
+		"optimism":     {ChainID: 10, RPCURL: "https://mainnet.optimism.io"},
+		"arbitrum":     {ChainID: 42161, RPCURL: "https://arb1.arbitrum.io/rpc"},
+		"base":         {ChainID: 8453, RPCURL: "https://mainnet.base.org"},
+		"base-sepolia": {ChainID: 84532, RPCURL: "https://sepolia.base.org"},
+		"polygon":      {ChainID: 137, RPCURL: "https://polygon-rpc.com"},
+	}
+}
+
+// DialNetwork dials the DefaultNetworks entry name. rpcURLs[name], if set, replaces the
+// public endpoint (typically a provider URL carrying an API key). The node's chain id is
+// checked against the preset, so a URL pasted under the wrong network fails here with
+// ErrChainIDMismatch instead of signing for the wrong chain later. The client is labelled
+// with name and "public" or "configured" (see WithLabels).
+func DialNetwork(ctx context.Context, name string, rpcURLs map[string]string) (*LiveBlockchainClient, Network, error) {
+	network, ok := DefaultNetworks()[name]
+	if !ok {
+		return nil, Network{}, fmt.Errorf("evm: unknown network %q", name)
+	}
+	endpoint := "public"
+	if url := rpcURLs[name]; url != "" {
+		network.RPCURL = url
+		endpoint = "configured"
+	}
+
+	dialed, err := DialLiveBlockchainClient(ctx, network.RPCURL)
+	if err != nil {
+		return nil, Network{}, err
+	}
+	client := dialed.WithLabels(name, endpoint)
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		client.Close()
+		return nil, Network{}, client.WrapRPCError(ctx, "eth_chainId", err)
+	}
+	if !chainID.IsUint64() || chainID.Uint64() != network.ChainID {
+		client.Close()
+		return nil, Network{}, fmt.Errorf("%w: %s expects %d, endpoint reports %s", ErrChainIDMismatch, name, network.ChainID, chainID)
+	}
+	return client, network, nil
+}
+
+// NetworkClients shares one connection per network between callers, e.g. the handlers of
+// a server serving several chains. Client hands out a separate *LiveBlockchainClient per
+// call, pinned to one network, so a request can relabel its copy without affecting others
+// and nothing is switched on a shared client.
+type NetworkClients struct {
+	rpcURLs map[string]string
+
+	mu      sync.Mutex
+	entries map[string]*networkEntry
+	closed  bool
+}
+
+type networkEntry struct {
+	mu     sync.Mutex
+	client *LiveBlockchainClient
+}
+
+// NewNetworkClients dials networks lazily with DialNetwork and rpcURLs.
+func NewNetworkClients(rpcURLs map[string]string) *NetworkClients {
+	urls := make(map[string]string, len(rpcURLs))
+	for name, url := range rpcURLs {
+		urls[name] = url
+	}
+	return &NetworkClients{rpcURLs: urls, entries: make(map[string]*networkEntry)}
+}
+
+// Client returns a client for the network name, dialing it on first use. A failed dial
+// is not cached; the next call tries again. Only callers of the same network wait on a
+// dial in progress.
+func (n *NetworkClients) Client(ctx context.Context, name string) (*LiveBlockchainClient, error) {
+	if _, ok := DefaultNetworks()[name]; !ok {
+		return nil, fmt.Errorf("evm: unknown network %q", name)
+	}
+
+	n.mu.Lock()
+	if n.closed {
+		n.mu.Unlock()
+		return nil, ErrNetworkClientsClosed
+	}
+	e, ok := n.entries[name]
+	if !ok {
+		e = &networkEntry{}
+		n.entries[name] = e
+	}
+	n.mu.Unlock()
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.client == nil {
+		client, _, err := DialNetwork(ctx, name, n.rpcURLs)
+		if err != nil {
+			return nil, err
+		}
+		n.mu.Lock()
+		closed := n.closed
+		n.mu.Unlock()
+		if closed {
+			client.Close()
+			return nil, ErrNetworkClientsClosed
+		}
+		e.client = client
+	}
+	return e.client.Clone(), nil
+}
+
+// Close closes every dialed connection. Clients handed out earlier stop working.
+func (n *NetworkClients) Close() {
+	n.mu.Lock()
+	n.closed = true
+	entries := n.entries
+	n.entries = nil
+	n.mu.Unlock()
+
+	for _, e := range entries {
+		e.mu.Lock()
+		if e.client != nil {
+			e.client.Close()
+		}
+		e.mu.Unlock()
+	}
+}
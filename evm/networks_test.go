@@ -0,0 +1,26 @@
+package evm
+
+import "testing"
+
+func TestDefaultNetworks(t *testing.T) {
+	networks := DefaultNetworks()
+	seen := make(map[uint64]string, len(networks))
+	for name, n := range networks {
+		if n.ChainID == 0 || n.RPCURL == "" {
+			t.Errorf("%s: incomplete preset %+v", name, n)
+		}
+		if other, dup := seen[n.ChainID]; dup {
+			t.Errorf("%s and %s share chain id %d", name, other, n.ChainID)
+		}
+		seen[n.ChainID] = name
+	}
+	if networks["mainnet"].ChainID != 1 || networks["mainnet"].ENSRegistry != MainnetENSRegistry {
+		t.Errorf("mainnet preset = %+v", networks["mainnet"])
+	}
+
+	// Each call returns its own copy.
+	networks["mainnet"] = Network{}
+	if DefaultNetworks()["mainnet"].ChainID != 1 {
+		t.Error("DefaultNetworks shares its map between calls")
+	}
+}
@@ -0,0 +1,116 @@
+package evm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// NextNonce returns the nonce to use for addr's next transaction: the pending
+// transaction count, which includes transactions still in the mempool. Use this,
+// not ConfirmedTxCount, when building a transaction.
+func NextNonce(ctx context.Context, client BlockchainClient, addr common.Address) (uint64, error) {
+	n, err := client.PendingNonceAt(ctx, addr)
+	if err != nil {
+		return 0, fmt.Errorf("evm: pending nonce of %s: %w", addr.Hex(), err)
+	}
+	return n, nil
+}
+
+// ConfirmedTxCount returns how many of addr's transactions are mined as of the latest
+// block. It lags NextNonce while transactions are pending, so using it as a nonce
+// collides with (or replaces) those pending transactions.
+func ConfirmedTxCount(ctx context.Context, client BlockchainClient, addr common.Address) (uint64, error) {
+	n, err := client.NonceAt(ctx, addr, nil)
+	if err != nil {
+		return 0, fmt.Errorf("evm: confirmed tx count of %s: %w", addr.Hex(), err)
+	}
+	return n, nil
+}
+
+// waitForNonceLookback bounds the block scan when the nonce was already used before
+// WaitForNonce started watching.
+const waitForNonceLookback = 128
+
+// ErrNonceTxNotFound is returned when addr's nonce moved past the target but the
+// transaction that used it wasn't found in the scanned blocks.
+var ErrNonceTxNotFound = errors.New("evm: transaction for nonce not found")
+
+// WaitForNonce polls until a transaction from addr with the given nonce is mined and
+// returns its hash. Unlike waiting on a hash, this also catches a replacement (e.g. a
+// fee bump) that landed instead of the original.
+//
+// It polls NonceAt(latest) every poll until it exceeds nonce, then scans the blocks
+// mined since it started (or the last waitForNonceLookback blocks, if the nonce was
+// already used) for the transaction.
+func WaitForNonce(ctx context.Context, client BlockchainClient, addr common.Address, nonce uint64, poll time.Duration) (common.Hash, error) {
+	if poll <= 0 {
+		poll = time.Second
+	}
+
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("evm: chain id: %w", err)
+	}
+	start, err := client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("evm: head: %w", err)
+	}
+	from := start.Number.Uint64()
+
+	ticker := time.NewTicker(poll)
+	defer ticker.Stop()
+	for first := true; ; first = false {
+		current, err := client.NonceAt(ctx, addr, nil)
+		if err != nil {
+			return common.Hash{}, fmt.Errorf("evm: nonce of %s: %w", addr.Hex(), err)
+		}
+		if current > nonce {
+			if first && from > waitForNonceLookback {
+				from -= waitForNonceLookback
+			} else if first {
+				from = 0
+			}
+			return findNonceTx(ctx, client, types.LatestSignerForChainID(chainID), addr, nonce, from)
+		}
+
+		select {
+		case <-ctx.Done():
+			return common.Hash{}, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// findNonceTx scans from the head back to block from for addr's transaction at nonce.
+func findNonceTx(ctx context.Context, client BlockchainClient, signer types.Signer, addr common.Address, nonce uint64, from uint64) (common.Hash, error) {
+	head, err := client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("evm: head: %w", err)
+	}
+
+	for n := head.Number.Uint64(); ; n-- {
+		block, err := client.BlockByNumber(ctx, new(big.Int).SetUint64(n))
+		if err != nil {
+			return common.Hash{}, fmt.Errorf("evm: block %d: %w", n, err)
+		}
+		for _, tx := range block.Transactions() {
+			if tx.Nonce() != nonce {
+				continue
+			}
+			sender, err := types.Sender(signer, tx)
+			if err == nil && sender == addr {
+				return tx.Hash(), nil
+			}
+		}
+		if n <= from {
+			break
+		}
+	}
+	return common.Hash{}, fmt.Errorf("%w: %s nonce %d", ErrNonceTxNotFound, addr.Hex(), nonce)
+}
@@ -0,0 +1,54 @@
+package evm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/ethclient/gethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// ErrBlockOverridesUnsupported is returned when the node rejects eth_call's block overrides parameter.
+var ErrBlockOverridesUnsupported = errors.New("evm: node does not support eth_call block overrides")
+
+// BlockOverrides replaces header fields (number, timestamp, coinbase, base fee, ...) for
+// a single eth_call. Zero fields are left as the node's block at the call tag.
+type BlockOverrides = gethclient.BlockOverrides
+
+const jsonRPCInvalidParams = -32602
+
+// CallContractWithBlockOverrides runs eth_call at blockTag with the header fields in
+// overrides replaced, e.g. to evaluate a time-dependent contract at a future timestamp.
+// Nodes that don't accept the fourth eth_call parameter yield ErrBlockOverridesUnsupported.
+func (c *LiveBlockchainClient) CallContractWithBlockOverrides(ctx context.Context, msg ethereum.CallMsg, blockTag BlockTag, overrides BlockOverrides) ([]byte, error) {
+	if blockTag == "" {
+		blockTag = BlockLatest
+	}
+	number, err := blockTag.BlockNumber()
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := gethclient.New(c.Client.Client()).CallContractWithBlockOverrides(ctx, msg, number, nil, overrides)
+	if err != nil {
+		if isUnsupportedParamErr(err) {
+			return nil, fmt.Errorf("%w: %v", ErrBlockOverridesUnsupported, err)
+		}
+		return nil, c.WrapRPCError(ctx, "eth_call", err)
+	}
+	return out, nil
+}
+
+// isUnsupportedParamErr recognises the ways nodes reject an extra positional parameter.
+func isUnsupportedParamErr(err error) bool {
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) && rpcErr.ErrorCode() == jsonRPCInvalidParams {
+		return true
+	}
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "too many arguments") ||
+		strings.Contains(msg, "invalid params")
+}
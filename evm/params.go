@@ -0,0 +1,53 @@
+package evm
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// HexBig wraps n as a JSON-RPC quantity param ("0x20000000000001"). Pass *big.Int values
+// of raw CallContext/BatchCallContext params through it: encoding/json writes a bare
+// *big.Int as a JSON number, which JavaScript nodes and proxies read as a float64 and
+// silently round above 2^53. A nil n encodes as null.
+func HexBig(n *big.Int) *hexutil.Big {
+	return (*hexutil.Big)(n)
+}
+
+// toCallArg encodes msg as an eth_call/debug_traceCall transaction object, with every
+// quantity hex-encoded. It mirrors ethclient's (unexported) encoding.
+func toCallArg(msg ethereum.CallMsg) map[string]interface{} {
+	arg := map[string]interface{}{
+		"from": msg.From,
+		"to":   msg.To,
+	}
+	if len(msg.Data) > 0 {
+		arg["input"] = hexutil.Bytes(msg.Data)
+	}
+	if msg.Value != nil {
+		arg["value"] = HexBig(msg.Value)
+	}
+	if msg.Gas != 0 {
+		arg["gas"] = hexutil.Uint64(msg.Gas)
+	}
+	if msg.GasPrice != nil {
+		arg["gasPrice"] = HexBig(msg.GasPrice)
+	}
+	if msg.GasFeeCap != nil {
+		arg["maxFeePerGas"] = HexBig(msg.GasFeeCap)
+	}
+	if msg.GasTipCap != nil {
+		arg["maxPriorityFeePerGas"] = HexBig(msg.GasTipCap)
+	}
+	if msg.AccessList != nil {
+		arg["accessList"] = msg.AccessList
+	}
+	if msg.BlobGasFeeCap != nil {
+		arg["maxFeePerBlobGas"] = HexBig(msg.BlobGasFeeCap)
+	}
+	if msg.BlobHashes != nil {
+		arg["blobVersionedHashes"] = msg.BlobHashes
+	}
+	return arg
+}
@@ -0,0 +1,95 @@
+package evm
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// 2^53 is the largest integer a float64 (a JSON number in JavaScript) holds exactly.
+var twoTo53 = new(big.Int).Lsh(big.NewInt(1), 53)
+
+func TestHexBigMarshal(t *testing.T) {
+	maxU256 := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+	tests := []struct {
+		n    *big.Int
+		want string
+	}{
+		{nil, `null`},
+		{big.NewInt(0), `"0x0"`},
+		{new(big.Int).Sub(twoTo53, big.NewInt(1)), `"0x1fffffffffffff"`},
+		{twoTo53, `"0x20000000000000"`},
+		{new(big.Int).Add(twoTo53, big.NewInt(1)), `"0x20000000000001"`},
+		{maxU256, `"0xffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"`},
+	}
+	for _, tt := range tests {
+		got, err := json.Marshal(HexBig(tt.n))
+		if err != nil {
+			t.Fatalf("marshal %v: %v", tt.n, err)
+		}
+		if string(got) != tt.want {
+			t.Errorf("HexBig(%v) = %s, want %s", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestToCallArgQuantities(t *testing.T) {
+	to := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	above := new(big.Int).Add(twoTo53, big.NewInt(1))
+	msg := ethereum.CallMsg{
+		To:        &to,
+		Gas:       1<<53 + 1,
+		Value:     above,
+		GasFeeCap: above,
+		GasTipCap: big.NewInt(1),
+	}
+
+	raw, err := json.Marshal(toCallArg(msg))
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var got map[string]interface{}
+	if err := json.Unmarshal(raw, &got); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	want := map[string]string{
+		"gas":                  "0x20000000000001",
+		"value":                "0x20000000000001",
+		"maxFeePerGas":         "0x20000000000001",
+		"maxPriorityFeePerGas": "0x1",
+	}
+	for field, w := range want {
+		s, ok := got[field].(string)
+		if !ok {
+			t.Errorf("%s = %v (%T), want hex string %s", field, got[field], got[field], w)
+			continue
+		}
+		if s != w {
+			t.Errorf("%s = %s, want %s", field, s, w)
+		}
+	}
+	if _, ok := got["gasPrice"]; ok {
+		t.Errorf("gasPrice set for a nil GasPrice")
+	}
+}
+
+func TestToBlockNumArg(t *testing.T) {
+	tests := []struct {
+		n    *big.Int
+		want string
+	}{
+		{nil, "latest"},
+		{new(big.Int).Sub(twoTo53, big.NewInt(1)), "0x1fffffffffffff"},
+		{new(big.Int).Add(twoTo53, big.NewInt(1)), "0x20000000000001"},
+		{big.NewInt(-1), "pending"},
+	}
+	for _, tt := range tests {
+		if got := toBlockNumArg(tt.n); got != tt.want {
+			t.Errorf("toBlockNumArg(%v) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package evm
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var ErrInvalidSignature = errors.New("evm: invalid signature")
+
+// HashPersonalMessage returns the EIP-191 hash wallets sign for personal_sign / eth_sign:
+// keccak256("\x19Ethereum Signed Message:\n" + len(data) + data).
+func HashPersonalMessage(data []byte) common.Hash {
+	return common.BytesToHash(accounts.TextHash(data))
+}
+
+// RecoverSigner returns the address that produced sig over hash.
+//
+// sig is either 65 bytes (r || s || v, with v as 0/1 or 27/28) or a 64-byte
+// EIP-2098 compact signature (r || yParityAndS).
+func RecoverSigner(hash common.Hash, sig []byte) (common.Address, error) {
+	normalized, err := normalizeSignature(sig)
+	if err != nil {
+		return common.Address{}, err
+	}
+
+	pub, err := crypto.SigToPub(hash.Bytes(), normalized)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	return crypto.PubkeyToAddress(*pub), nil
+}
+
+// normalizeSignature returns a fresh 65-byte r || s || v copy with v in {0, 1}.
+func normalizeSignature(sig []byte) ([]byte, error) {
+	out := make([]byte, crypto.SignatureLength)
+
+	switch len(sig) {
+	case crypto.SignatureLength:
+		copy(out, sig)
+	case crypto.SignatureLength - 1:
+		// EIP-2098: the top bit of s carries the y parity.
+		copy(out, sig)
+		out[64] = out[32] >> 7
+		out[32] &= 0x7f
+	default:
+		return nil, fmt.Errorf("%w: length %d, want 64 or 65", ErrInvalidSignature, len(sig))
+	}
+
+	if out[64] >= 27 {
+		out[64] -= 27
+	}
+	if out[64] > 1 {
+		return nil, fmt.Errorf("%w: recovery id %d", ErrInvalidSignature, out[64])
+	}
+	return out, nil
+}
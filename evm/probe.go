@@ -0,0 +1,48 @@
+package evm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const jsonRPCMethodNotFound = -32601
+
+// ProbeMethods reports which of methods the endpoint supports, e.g. to decide at startup
+// whether debug_traceCall or eth_getBlockReceipts can be used. Each method is called once
+// with no params in a single batch: "method not found" (-32601) means unsupported, any
+// other answer, including an invalid-params error, means supported.
+func (c *LiveBlockchainClient) ProbeMethods(ctx context.Context, methods []string) (map[string]bool, error) {
+	out := make(map[string]bool, len(methods))
+	if len(methods) == 0 {
+		return out, nil
+	}
+
+	batch := make([]rpc.BatchElem, len(methods))
+	for i, m := range methods {
+		batch[i] = rpc.BatchElem{Method: m, Result: new(json.RawMessage)}
+	}
+	if err := c.Client.Client().BatchCallContext(ctx, batch); err != nil {
+		return nil, c.WrapRPCError(ctx, "batch", err)
+	}
+
+	for i, elem := range batch {
+		out[methods[i]] = elem.Error == nil || !isMethodNotFoundErr(elem.Error)
+	}
+	return out, nil
+}
+
+// isMethodNotFoundErr also matches providers that use a generic code with the standard message.
+func isMethodNotFoundErr(err error) bool {
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) && rpcErr.ErrorCode() == jsonRPCMethodNotFound {
+		return true
+	}
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "method not found") ||
+		strings.Contains(msg, "does not exist/is not available") ||
+		strings.Contains(msg, "unsupported method")
+}
@@ -0,0 +1,122 @@
+package evm
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient/gethclient"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// ErrProofInvalid is returned when an eth_getProof response doesn't verify against the
+// block's state root, i.e. the node returned wrong or inconsistent data.
+var ErrProofInvalid = errors.New("evm: storage proof does not verify")
+
+// VerifyStorageValue reads storage slot of address with eth_getProof (EIP-1186) and
+// checks the Merkle proofs against the state root of the block's header, so the answer
+// doesn't depend on trusting the node beyond the header itself. It reports whether the
+// slot holds expected (compared as 32-byte words, so leading zeros don't matter; an
+// unset slot equals zero). Proof failures wrap ErrProofInvalid with the failing step.
+func (c *LiveBlockchainClient) VerifyStorageValue(ctx context.Context, address common.Address, slot string, expected []byte, blockTag BlockTag) (bool, error) {
+	if blockTag == "" {
+		blockTag = BlockLatest
+	}
+	number, err := blockTag.BlockNumber()
+	if err != nil {
+		return false, err
+	}
+	slotHash, err := parseSlot(slot)
+	if err != nil {
+		return false, err
+	}
+
+	// Pin the proof to the header we verify against, whatever the tag resolves to later.
+	header, err := c.HeaderByNumber(ctx, number)
+	if err != nil {
+		return false, fmt.Errorf("evm: header for %s: %w", blockTag, err)
+	}
+	res, err := gethclient.New(c.Client.Client()).GetProof(ctx, address, []string{slotHash.Hex()}, header.Number)
+	if err != nil {
+		return false, c.WrapRPCError(ctx, "eth_getProof", err)
+	}
+	if len(res.StorageProof) != 1 {
+		return false, fmt.Errorf("%w: got %d storage proofs, want 1", ErrProofInvalid, len(res.StorageProof))
+	}
+
+	// Account proof: state root -> account, whose storage root anchors the slot proof.
+	accountRLP, err := verifyMerkleProof(header.Root, crypto.Keccak256(address.Bytes()), res.AccountProof)
+	if err != nil {
+		return false, fmt.Errorf("%w: account proof for %s at block %d: %v", ErrProofInvalid, address.Hex(), header.Number, err)
+	}
+	storageRoot := types.EmptyRootHash
+	if len(accountRLP) > 0 {
+		var acc types.StateAccount
+		if err := rlp.DecodeBytes(accountRLP, &acc); err != nil {
+			return false, fmt.Errorf("%w: decode account: %v", ErrProofInvalid, err)
+		}
+		storageRoot = acc.Root
+	}
+	if storageRoot != res.StorageHash {
+		return false, fmt.Errorf("%w: proven storage root %s, node reported %s", ErrProofInvalid, storageRoot.Hex(), res.StorageHash.Hex())
+	}
+
+	valueRLP, err := verifyMerkleProof(storageRoot, crypto.Keccak256(slotHash.Bytes()), res.StorageProof[0].Proof)
+	if err != nil {
+		return false, fmt.Errorf("%w: storage proof for slot %s: %v", ErrProofInvalid, slotHash.Hex(), err)
+	}
+	var proven []byte
+	if len(valueRLP) > 0 {
+		if err := rlp.DecodeBytes(valueRLP, &proven); err != nil {
+			return false, fmt.Errorf("%w: decode slot value: %v", ErrProofInvalid, err)
+		}
+	}
+	if res.StorageProof[0].Value != nil && res.StorageProof[0].Value.Cmp(common.BytesToHash(proven).Big()) != 0 {
+		return false, fmt.Errorf("%w: node reported value %s, proof holds %s", ErrProofInvalid,
+			res.StorageProof[0].Value, common.BytesToHash(proven).Big())
+	}
+
+	if len(expected) > common.HashLength {
+		return false, fmt.Errorf("evm: expected value is %d bytes, a slot holds 32", len(expected))
+	}
+	return bytes.Equal(common.BytesToHash(proven).Bytes(), common.BytesToHash(expected).Bytes()), nil
+}
+
+// parseSlot accepts a hex slot ("0x0", "0x…32 bytes").
+func parseSlot(slot string) (common.Hash, error) {
+	b, err := hexutil.Decode(slot)
+	if err != nil {
+		n, errBig := hexutil.DecodeBig(slot)
+		if errBig != nil {
+			return common.Hash{}, fmt.Errorf("evm: invalid storage slot %q: %w", slot, err)
+		}
+		return common.BigToHash(n), nil
+	}
+	if len(b) > common.HashLength {
+		return common.Hash{}, fmt.Errorf("evm: storage slot %q longer than 32 bytes", slot)
+	}
+	return common.BytesToHash(b), nil
+}
+
+// verifyMerkleProof checks proof (hex RLP trie nodes) for key under root and returns the
+// proven value, or nil if the proof shows the key is absent.
+func verifyMerkleProof(root common.Hash, key []byte, proof []string) ([]byte, error) {
+	db := memorydb.New()
+	for _, node := range proof {
+		b, err := hexutil.Decode(node)
+		if err != nil {
+			return nil, fmt.Errorf("proof node: %w", err)
+		}
+		if err := db.Put(crypto.Keccak256(b), b); err != nil {
+			return nil, err
+		}
+	}
+	return trie.VerifyProof(root, key, db)
+}
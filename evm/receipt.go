@@ -0,0 +1,111 @@
+package evm
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// standardReceiptFields are the eth_getTransactionReceipt fields of the execution API;
+// anything else a provider returns ends up in TxReceipt.Extra.
+var standardReceiptFields = map[string]bool{
+	"type": true, "root": true, "status": true, "cumulativeGasUsed": true,
+	"logsBloom": true, "logs": true, "transactionHash": true, "contractAddress": true,
+	"gasUsed": true, "effectiveGasPrice": true, "blobGasUsed": true, "blobGasPrice": true,
+	"blockHash": true, "blockNumber": true, "transactionIndex": true, "from": true, "to": true,
+}
+
+// TxReceipt is a transaction receipt together with the provider-specific fields that
+// types.Receipt drops, such as the L1 data fees rollups report.
+type TxReceipt struct {
+	Receipt *types.Receipt
+
+	// Non-standard fields, verbatim
+	Extra map[string]json.RawMessage
+
+	// L1 fee data parsed from Extra; nil if the provider reported none
+	L2Fees *L2Fees
+}
+
+// L2Fees holds the L1 data cost reported in rollup receipts. Fields the provider
+// didn't report, or reported in an unexpected format, are nil; the raw value is
+// still in TxReceipt.Extra.
+type L2Fees struct {
+	L1Fee      *big.Int // OP Stack: l1Fee, wei charged for L1 data
+	L1GasUsed  *big.Int // OP Stack: l1GasUsed
+	L1GasPrice *big.Int // OP Stack: l1GasPrice
+
+	GasUsedForL1  *big.Int // Arbitrum: gasUsedForL1, L2 gas spent on L1 calldata
+	L1BlockNumber *big.Int // Arbitrum: l1BlockNumber
+}
+
+func (r *TxReceipt) UnmarshalJSON(data []byte) error {
+	receipt := new(types.Receipt)
+	if err := json.Unmarshal(data, receipt); err != nil {
+		return err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	var extra map[string]json.RawMessage
+	for k, v := range fields {
+		if standardReceiptFields[k] {
+			continue
+		}
+		if extra == nil {
+			extra = make(map[string]json.RawMessage)
+		}
+		extra[k] = v
+	}
+
+	*r = TxReceipt{Receipt: receipt, Extra: extra, L2Fees: parseL2Fees(extra)}
+	return nil
+}
+
+func parseL2Fees(extra map[string]json.RawMessage) *L2Fees {
+	quantity := func(key string) *big.Int {
+		raw, ok := extra[key]
+		if !ok {
+			return nil
+		}
+		var v hexutil.Big
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return nil
+		}
+		return v.ToInt()
+	}
+
+	fees := &L2Fees{
+		L1Fee:         quantity("l1Fee"),
+		L1GasUsed:     quantity("l1GasUsed"),
+		L1GasPrice:    quantity("l1GasPrice"),
+		GasUsedForL1:  quantity("gasUsedForL1"),
+		L1BlockNumber: quantity("l1BlockNumber"),
+	}
+	if *fees == (L2Fees{}) {
+		return nil
+	}
+	return fees
+}
+
+// TransactionReceiptWithExtra calls eth_getTransactionReceipt like TransactionReceipt,
+// but keeps the fields types.Receipt doesn't know about. Use it on rollups, where the
+// L1 data fee is often most of the cost. Returns ethereum.NotFound for unknown or
+// pending transactions.
+func (c *LiveBlockchainClient) TransactionReceiptWithExtra(ctx context.Context, hash common.Hash) (*TxReceipt, error) {
+	var receipt *TxReceipt
+	if err := c.Client.Client().CallContext(ctx, &receipt, "eth_getTransactionReceipt", hash); err != nil {
+		return nil, c.WrapRPCError(ctx, "eth_getTransactionReceipt", err)
+	}
+	if receipt == nil {
+		return nil, ethereum.NotFound
+	}
+	return receipt, nil
+}
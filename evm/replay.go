@@ -0,0 +1,226 @@
+package evm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// ReplayResult is the outcome of re-executing a live transaction in the sim.
+type ReplayResult struct {
+	From       common.Address
+	GasUsed    uint64 // gas the re-execution used, refunds applied
+	ReturnData []byte // return data, or the revert data if Reverted
+	Logs       []types.Log
+	Reverted   bool
+	// RevertReason is the decoded Error(string) reason or, for a VM error such as out of
+	// gas, the node's message.
+	RevertReason string
+
+	// PrestateSource is "prestateTracer" when the live node served
+	// debug_traceTransaction, otherwise "accounts" (from/to balance, nonce and code only).
+	PrestateSource string
+}
+
+type prestateAccount struct {
+	Balance *hexutil.Big                `json:"balance"`
+	Nonce   uint64                      `json:"nonce"`
+	Code    hexutil.Bytes               `json:"code"`
+	Storage map[common.Hash]common.Hash `json:"storage"`
+}
+
+// simCallResult is one call of an eth_simulateV1 block.
+type simCallResult struct {
+	ReturnData hexutil.Bytes  `json:"returnData"`
+	GasUsed    hexutil.Uint64 `json:"gasUsed"`
+	Status     hexutil.Uint64 `json:"status"`
+	Logs       []struct {
+		Address common.Address `json:"address"`
+		Topics  []common.Hash  `json:"topics"`
+		Data    hexutil.Bytes  `json:"data"`
+	} `json:"logs"`
+	Error *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Data    string `json:"data"`
+	} `json:"error"`
+}
+
+// ReplayTransaction fetches txHash from live and re-executes it on s with the state the
+// tx touched as of its parent block, returning its gas used, logs and revert reason.
+//
+// The sim can't sign as the original sender (and the tx is signed for another chain),
+// so the tx runs through eth_simulateV1 with signature and nonce checks off and the
+// prestate as state overrides: s's own chain is left unchanged. Gas used and logs are
+// those of the re-execution; the logs carry only address, topics and data. Block number
+// and timestamp are the sim's, not the original block's.
+func (s *SimulatedBlockchainClient) ReplayTransaction(ctx context.Context, live BlockchainClient, txHash common.Hash) (*ReplayResult, error) {
+	tx, pending, err := live.TransactionByHash(ctx, txHash)
+	if err != nil {
+		return nil, fmt.Errorf("evm: fetch tx: %w", err)
+	}
+	if pending {
+		return nil, errors.New("evm: cannot replay a pending transaction")
+	}
+
+	receipt, err := live.TransactionReceipt(ctx, txHash)
+	if err != nil {
+		return nil, fmt.Errorf("evm: fetch receipt: %w", err)
+	}
+
+	chainID, err := live.ChainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("evm: chain id: %w", err)
+	}
+	from, err := types.Sender(types.LatestSignerForChainID(chainID), tx)
+	if err != nil {
+		return nil, fmt.Errorf("evm: recover sender: %w", err)
+	}
+
+	res := &ReplayResult{From: from}
+
+	alloc, err := tracePrestate(ctx, live, txHash)
+	if err == nil {
+		res.PrestateSource = "prestateTracer"
+	} else {
+		parent := new(big.Int).Sub(receipt.BlockNumber, big.NewInt(1))
+		alloc, err = accountPrestate(ctx, live, parent, &from, tx.To())
+		if err != nil {
+			return nil, err
+		}
+		res.PrestateSource = "accounts"
+	}
+
+	rc, ok := s.client.(interface{ Client() *rpc.Client })
+	if !ok {
+		return nil, errors.New("evm: simulated client exposes no RPC client")
+	}
+
+	msg := ethereum.CallMsg{
+		From:       from,
+		To:         tx.To(),
+		Gas:        tx.Gas(),
+		Value:      tx.Value(),
+		Data:       tx.Data(),
+		AccessList: tx.AccessList(),
+	}
+	opts := map[string]interface{}{
+		"blockStateCalls": []interface{}{map[string]interface{}{
+			"stateOverrides": stateOverrides(alloc),
+			"calls":          []interface{}{toCallArg(msg)},
+		}},
+		"validation": false,
+	}
+
+	var blocks []struct {
+		Calls []simCallResult `json:"calls"`
+	}
+	if err := rc.Client().CallContext(ctx, &blocks, "eth_simulateV1", opts, "latest"); err != nil {
+		return nil, fmt.Errorf("evm: replay: %w", err)
+	}
+	if len(blocks) != 1 || len(blocks[0].Calls) != 1 {
+		return nil, errors.New("evm: replay: unexpected eth_simulateV1 result")
+	}
+	call := blocks[0].Calls[0]
+
+	res.GasUsed = uint64(call.GasUsed)
+	res.ReturnData = call.ReturnData
+	for _, l := range call.Logs {
+		res.Logs = append(res.Logs, types.Log{Address: l.Address, Topics: l.Topics, Data: l.Data})
+	}
+	if call.Status == 0 {
+		res.Reverted = true
+		if call.Error != nil {
+			res.RevertReason = call.Error.Message
+			if data, err := hexutil.Decode(call.Error.Data); err == nil {
+				res.ReturnData = data
+				if reason, err := abi.UnpackRevert(data); err == nil {
+					res.RevertReason = reason
+				}
+			}
+		}
+	}
+	return res, nil
+}
+
+// stateOverrides turns a prestate into eth_simulateV1 state overrides. "state" replaces
+// an account's whole storage, so slots the prestate doesn't list read as zero.
+func stateOverrides(alloc types.GenesisAlloc) map[common.Address]interface{} {
+	out := make(map[common.Address]interface{}, len(alloc))
+	for addr, acct := range alloc {
+		storage := acct.Storage
+		if storage == nil {
+			storage = map[common.Hash]common.Hash{}
+		}
+		out[addr] = map[string]interface{}{
+			"balance": HexBig(acct.Balance),
+			"nonce":   hexutil.Uint64(acct.Nonce),
+			"code":    hexutil.Bytes(acct.Code),
+			"state":   storage,
+		}
+	}
+	return out
+}
+
+// tracePrestate asks the live node for every account and slot the tx touched.
+func tracePrestate(ctx context.Context, live BlockchainClient, txHash common.Hash) (types.GenesisAlloc, error) {
+	lc, ok := live.(*LiveBlockchainClient)
+	if !ok {
+		return nil, errors.New("evm: prestate tracing needs a live RPC client")
+	}
+
+	var trace map[common.Address]prestateAccount
+	err := lc.Client.Client().CallContext(ctx, &trace, "debug_traceTransaction", txHash,
+		map[string]interface{}{"tracer": "prestateTracer"})
+	if err != nil {
+		return nil, err
+	}
+
+	alloc := make(types.GenesisAlloc, len(trace))
+	for addr, acct := range trace {
+		balance := new(big.Int)
+		if acct.Balance != nil {
+			balance = acct.Balance.ToInt()
+		}
+		alloc[addr] = types.Account{
+			Balance: balance,
+			Nonce:   acct.Nonce,
+			Code:    acct.Code,
+			Storage: acct.Storage,
+		}
+	}
+	return alloc, nil
+}
+
+// accountPrestate is the fallback when tracing isn't available: balance, nonce and code
+// for the sender and recipient only. Contract storage starts empty.
+func accountPrestate(ctx context.Context, live BlockchainClient, block *big.Int, accounts ...*common.Address) (types.GenesisAlloc, error) {
+	alloc := make(types.GenesisAlloc, len(accounts))
+	for _, a := range accounts {
+		if a == nil {
+			continue
+		}
+		balance, err := live.BalanceAt(ctx, *a, block)
+		if err != nil {
+			return nil, fmt.Errorf("evm: prestate balance %s: %w", a.Hex(), err)
+		}
+		nonce, err := live.NonceAt(ctx, *a, block)
+		if err != nil {
+			return nil, fmt.Errorf("evm: prestate nonce %s: %w", a.Hex(), err)
+		}
+		code, err := live.CodeAt(ctx, *a, block)
+		if err != nil {
+			return nil, fmt.Errorf("evm: prestate code %s: %w", a.Hex(), err)
+		}
+		alloc[*a] = types.Account{Balance: balance, Nonce: nonce, Code: code}
+	}
+	return alloc, nil
+}
@@ -0,0 +1,153 @@
+package evm
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrRequestIDMismatch is returned when a JSON-RPC response carries an id that doesn't
+// belong to any request in the HTTP exchange.
+var ErrRequestIDMismatch = errors.New("evm: json-rpc response id does not match the request")
+
+// RequestIDTransport replaces the id of every JSON-RPC request sent over HTTP with
+// NextID(), e.g. to correlate calls in a proxy or tracing middleware by a scheme of the
+// caller's choosing, and maps the ids back before go-ethereum's rpc.Client sees the
+// response. A response whose id wasn't issued in that exchange (or is repeated) fails
+// the call with ErrRequestIDMismatch. Batches are rewritten element by element.
+//
+// Pass it to NewLiveBlockchainClientWithTransport. Websocket and IPC clients don't go
+// through an http.RoundTripper and keep rpc.Client's own incrementing ids.
+type RequestIDTransport struct {
+	Base   http.RoundTripper // nil means http.DefaultTransport
+	NextID func() string     // must not repeat within a client; nil means SequentialRequestIDs("")
+	once   sync.Once
+}
+
+// SequentialRequestIDs returns an id generator yielding prefix1, prefix2, ... Safe for
+// concurrent use.
+func SequentialRequestIDs(prefix string) func() string {
+	var n atomic.Uint64
+	return func() string {
+		return fmt.Sprintf("%s%d", prefix, n.Add(1))
+	}
+}
+
+func (t *RequestIDTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.once.Do(func() {
+		if t.NextID == nil {
+			t.NextID = SequentialRequestIDs("")
+		}
+	})
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if req.Body == nil {
+		return base.RoundTrip(req)
+	}
+
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("evm: read request body: %w", err)
+	}
+
+	issued := make(map[string]json.RawMessage)
+	body, err = rewriteIDs(body, func(id json.RawMessage) (json.RawMessage, error) {
+		newID, err := json.Marshal(t.NextID())
+		if err != nil {
+			return nil, err
+		}
+		if _, dup := issued[string(newID)]; dup {
+			return nil, fmt.Errorf("evm: request id %s issued twice", newID)
+		}
+		issued[string(newID)] = id
+		return newID, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("evm: rewrite request ids: %w", err)
+	}
+
+	req = req.Clone(req.Context())
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	req.ContentLength = int64(len(body))
+	req.GetBody = func() (io.ReadCloser, error) { return io.NopCloser(bytes.NewReader(body)), nil }
+
+	resp, err := base.RoundTrip(req)
+	if err != nil || len(issued) == 0 {
+		return resp, err
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("evm: read response body: %w", err)
+	}
+	rewritten, err := rewriteIDs(respBody, func(id json.RawMessage) (json.RawMessage, error) {
+		if string(id) == "null" {
+			return id, nil // error responses to unparseable requests have no id
+		}
+		orig, ok := issued[string(id)]
+		if !ok {
+			return nil, fmt.Errorf("%w: got id %s", ErrRequestIDMismatch, id)
+		}
+		delete(issued, string(id))
+		return orig, nil
+	})
+	switch {
+	case errors.Is(err, ErrRequestIDMismatch):
+		return nil, err
+	case err == nil:
+		respBody = rewritten
+	}
+	// Otherwise it isn't JSON-RPC (an HTTP error page, say); rpc.Client reports it.
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+	resp.ContentLength = int64(len(respBody))
+	return resp, nil
+}
+
+// rewriteIDs applies f to the id of a JSON-RPC message or of each message in a batch.
+// Messages without an id (notifications) are left alone.
+func rewriteIDs(body []byte, f func(json.RawMessage) (json.RawMessage, error)) ([]byte, error) {
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		var msgs []map[string]json.RawMessage
+		if err := json.Unmarshal(trimmed, &msgs); err != nil {
+			return nil, err
+		}
+		for _, m := range msgs {
+			if err := rewriteID(m, f); err != nil {
+				return nil, err
+			}
+		}
+		return json.Marshal(msgs)
+	}
+
+	var msg map[string]json.RawMessage
+	if err := json.Unmarshal(trimmed, &msg); err != nil {
+		return nil, err
+	}
+	if err := rewriteID(msg, f); err != nil {
+		return nil, err
+	}
+	return json.Marshal(msg)
+}
+
+func rewriteID(msg map[string]json.RawMessage, f func(json.RawMessage) (json.RawMessage, error)) error {
+	id, ok := msg["id"]
+	if !ok {
+		return nil
+	}
+	newID, err := f(id)
+	if err != nil {
+		return err
+	}
+	msg["id"] = newID
+	return nil
+}
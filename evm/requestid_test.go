@@ -0,0 +1,148 @@
+package evm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// echoNode answers every JSON-RPC request with result "0x1", recording the ids it saw.
+// If mangle is set, it changes each id before answering.
+type echoNode struct {
+	mu     sync.Mutex
+	ids    []string
+	mangle func(id json.RawMessage) json.RawMessage
+}
+
+func (n *echoNode) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	trimmed := bytes.TrimSpace(body)
+	batch := len(trimmed) > 0 && trimmed[0] == '['
+	var msgs []map[string]json.RawMessage
+	if batch {
+		err = json.Unmarshal(trimmed, &msgs)
+	} else {
+		msgs = make([]map[string]json.RawMessage, 1)
+		err = json.Unmarshal(trimmed, &msgs[0])
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	resps := make([]map[string]json.RawMessage, len(msgs))
+	n.mu.Lock()
+	for i, m := range msgs {
+		n.ids = append(n.ids, string(m["id"]))
+		id := m["id"]
+		if n.mangle != nil {
+			id = n.mangle(id)
+		}
+		resps[i] = map[string]json.RawMessage{
+			"jsonrpc": json.RawMessage(`"2.0"`),
+			"id":      id,
+			"result":  json.RawMessage(`"0x1"`),
+		}
+	}
+	n.mu.Unlock()
+
+	var out []byte
+	if batch {
+		out, err = json.Marshal(resps)
+	} else {
+		out, err = json.Marshal(resps[0])
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &http.Response{
+		StatusCode:    http.StatusOK,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(out)),
+		ContentLength: int64(len(out)),
+		Request:       req,
+	}, nil
+}
+
+func TestRequestIDTransportIncrements(t *testing.T) {
+	node := &echoNode{}
+	rt := &RequestIDTransport{Base: node, NextID: SequentialRequestIDs("svc-")}
+	client, err := NewLiveBlockchainClientWithTransport(context.Background(), "http://node.invalid", rt)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer client.Close()
+
+	for i := 0; i < 3; i++ {
+		id, err := client.ChainID(context.Background())
+		if err != nil {
+			t.Fatalf("call %d: %v", i, err)
+		}
+		if id.Uint64() != 1 {
+			t.Fatalf("call %d: chain id %s, want 1", i, id)
+		}
+	}
+
+	want := []string{`"svc-1"`, `"svc-2"`, `"svc-3"`}
+	if strings.Join(node.ids, ",") != strings.Join(want, ",") {
+		t.Errorf("node saw ids %v, want %v", node.ids, want)
+	}
+}
+
+func TestRequestIDTransportRejectsMismatch(t *testing.T) {
+	node := &echoNode{mangle: func(json.RawMessage) json.RawMessage { return json.RawMessage(`"other"`) }}
+	rt := &RequestIDTransport{Base: node}
+	client, err := NewLiveBlockchainClientWithTransport(context.Background(), "http://node.invalid", rt)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer client.Close()
+
+	if _, err := client.ChainID(context.Background()); !errors.Is(err, ErrRequestIDMismatch) {
+		t.Fatalf("err = %v, want ErrRequestIDMismatch", err)
+	}
+}
+
+func TestRequestIDTransportBatch(t *testing.T) {
+	node := &echoNode{}
+	rt := &RequestIDTransport{Base: node, NextID: SequentialRequestIDs("b")}
+
+	body := `[{"jsonrpc":"2.0","id":7,"method":"eth_chainId"},{"jsonrpc":"2.0","id":8,"method":"eth_blockNumber"}]`
+	req, err := http.NewRequest(http.MethodPost, "http://node.invalid", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("round trip: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var got []struct {
+		ID json.RawMessage `json:"id"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(got) != 2 || string(got[0].ID) != "7" || string(got[1].ID) != "8" {
+		t.Errorf("response ids %v, want original 7 and 8", got)
+	}
+	if strings.Join(node.ids, ",") != `"b1","b2"` {
+		t.Errorf("node saw ids %v, want b1, b2", node.ids)
+	}
+
+	// A repeated id in one batch response is rejected.
+	node.mangle = func(json.RawMessage) json.RawMessage { return json.RawMessage(`"b3"`) }
+	req, _ = http.NewRequest(http.MethodPost, "http://node.invalid", strings.NewReader(body))
+	if _, err := rt.RoundTrip(req); !errors.Is(err, ErrRequestIDMismatch) {
+		t.Fatalf("err = %v, want ErrRequestIDMismatch", err)
+	}
+}
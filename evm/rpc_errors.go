@@ -0,0 +1,92 @@
+package evm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/quantumauth-io/quantum-go-utils/log"
+)
+
+// ErrRPCTimeout means the node or the transport timed out while the caller's ctx was
+// still live, so trying another endpoint or retrying may help.
+var ErrRPCTimeout = errors.New("evm: rpc timed out")
+
+// ClassifyRPCError makes the cause of a failed RPC call explicit:
+//   - the caller's ctx ended: the result matches ctx.Err() (context.Canceled or
+//     context.DeadlineExceeded), meaning give up;
+//   - a transport or server timeout with ctx still live: the result matches
+//     ErrRPCTimeout and deliberately not context.DeadlineExceeded, which Go's HTTP
+//     client timeouts would otherwise also match;
+//   - anything else (JSON-RPC errors included) is returned unchanged.
+func ClassifyRPCError(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		if errors.Is(err, ctxErr) {
+			return err
+		}
+		return fmt.Errorf("%w: %w", ctxErr, err)
+	}
+
+	var netErr net.Error
+	if (errors.As(err, &netErr) && netErr.Timeout()) ||
+		errors.Is(err, context.DeadlineExceeded) || errors.Is(err, os.ErrDeadlineExceeded) {
+		return fmt.Errorf("%w: %v", ErrRPCTimeout, err)
+	}
+	return err
+}
+
+// RPCError is a failed JSON-RPC call annotated with where it failed, so an error or log
+// line names the method, network and endpoint instead of just "execution reverted".
+// Endpoint is a caller-chosen name for the provider, never its URL, which often embeds an
+// API key. Network and Endpoint are empty for clients without labels (see WithLabels).
+type RPCError struct {
+	Method   string
+	Network  string
+	Endpoint string
+	Code     int   // JSON-RPC error code; 0 for transport and timeout errors
+	Err      error // classified by ClassifyRPCError
+}
+
+func (e *RPCError) Error() string {
+	where := e.Method
+	if e.Network != "" || e.Endpoint != "" {
+		where = fmt.Sprintf("%s on %s/%s", e.Method, e.Network, e.Endpoint)
+	}
+	return fmt.Sprintf("evm: %s: %v", where, e.Err)
+}
+
+func (e *RPCError) Unwrap() error { return e.Err }
+
+// LogFields returns the call context as key/value pairs for the log package.
+func (e *RPCError) LogFields() []interface{} {
+	return []interface{}{"rpcMethod", e.Method, "network", e.Network, "endpoint", e.Endpoint, "rpcCode", e.Code}
+}
+
+// LogRPCError logs err at error level, adding the RPCError fields as structured
+// fields when err wraps one.
+func LogRPCError(structuredLogMessage string, err error, logKeysWithValues ...interface{}) {
+	var rpcErr *RPCError
+	if errors.As(err, &rpcErr) {
+		logKeysWithValues = append(logKeysWithValues, rpcErr.LogFields()...)
+	}
+	log.ErrorErr(structuredLogMessage, err, logKeysWithValues...)
+}
+
+// newRPCError classifies err and records where it happened. It returns nil for a nil err.
+func newRPCError(ctx context.Context, method, network, endpoint string, err error) error {
+	if err == nil {
+		return nil
+	}
+	e := &RPCError{Method: method, Network: network, Endpoint: endpoint, Err: ClassifyRPCError(ctx, err)}
+	var codeErr rpc.Error
+	if errors.As(err, &codeErr) {
+		e.Code = codeErr.ErrorCode()
+	}
+	return e
+}
@@ -0,0 +1,69 @@
+package evm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// revertingNode answers every single JSON-RPC request with an execution-reverted error.
+type revertingNode struct{}
+
+func (revertingNode) RoundTrip(req *http.Request) (*http.Response, error) {
+	var msg struct {
+		ID json.RawMessage `json:"id"`
+	}
+	if err := json.NewDecoder(req.Body).Decode(&msg); err != nil {
+		return nil, err
+	}
+	out := []byte(`{"jsonrpc":"2.0","id":` + string(msg.ID) + `,"error":{"code":3,"message":"execution reverted"}}`)
+	return &http.Response{
+		StatusCode:    http.StatusOK,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(out)),
+		ContentLength: int64(len(out)),
+		Request:       req,
+	}, nil
+}
+
+func TestRPCErrorContext(t *testing.T) {
+	const url = "http://node.invalid/v2/secret-api-key"
+	dialed, err := NewLiveBlockchainClientWithTransport(context.Background(), url, revertingNode{})
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer dialed.Close()
+	client := dialed.WithLabels("mainnet", "alchemy")
+
+	_, err = client.BlockTransactionCount(context.Background(), BlockLatest)
+	var rpcErr *RPCError
+	if !errors.As(err, &rpcErr) {
+		t.Fatalf("err = %v (%T), want *RPCError", err, err)
+	}
+	if rpcErr.Method != "eth_getBlockTransactionCountByNumber" || rpcErr.Network != "mainnet" ||
+		rpcErr.Endpoint != "alchemy" || rpcErr.Code != 3 {
+		t.Errorf("RPCError = %+v", rpcErr)
+	}
+	msg := err.Error()
+	if !strings.Contains(msg, "mainnet/alchemy") || !strings.Contains(msg, "execution reverted") {
+		t.Errorf("message %q lacks call context", msg)
+	}
+	if strings.Contains(msg, "secret-api-key") {
+		t.Errorf("message %q leaks the endpoint URL", msg)
+	}
+}
+
+func TestRPCErrorUnlabelled(t *testing.T) {
+	err := newRPCError(context.Background(), "eth_call", "", "", errors.New("boom"))
+	if got, want := err.Error(), "evm: eth_call: boom"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+	if newRPCError(context.Background(), "eth_call", "", "", nil) != nil {
+		t.Error("nil err should stay nil")
+	}
+}
@@ -0,0 +1,47 @@
+package evm
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Typed versions of the txpool rejections a sender has to branch on. Nodes only report
+// these as JSON-RPC error text, so ClassifySendError matches on the message.
+var (
+	// ErrReplacementUnderpriced: a tx with the same nonce is pending and the new one
+	// doesn't bump the fees enough (geth wants +10%); bump further and resend.
+	ErrReplacementUnderpriced = errors.New("evm: replacement transaction underpriced")
+	// ErrAlreadyKnown: the node already has this exact tx; usually safe to treat as sent.
+	ErrAlreadyKnown = errors.New("evm: transaction already known")
+	// ErrNonceTooLow: the nonce was already used by a mined tx.
+	ErrNonceTooLow = errors.New("evm: nonce too low")
+	// ErrTxUnderpriced: the fees are below the node's minimum for admission.
+	ErrTxUnderpriced = errors.New("evm: transaction underpriced")
+)
+
+// ClassifySendError wraps a SendTransaction error with the matching typed error above,
+// keeping the original message. Unrecognised errors and nil are returned unchanged.
+// SendTx already applies it.
+func ClassifySendError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	msg := strings.ToLower(err.Error())
+	var typed error
+	switch {
+	// Check the replacement case before the generic "underpriced" one it contains.
+	case strings.Contains(msg, "replacement transaction underpriced"):
+		typed = ErrReplacementUnderpriced
+	case strings.Contains(msg, "already known"), strings.Contains(msg, "known transaction"):
+		typed = ErrAlreadyKnown
+	case strings.Contains(msg, "nonce too low"):
+		typed = ErrNonceTooLow
+	case strings.Contains(msg, "transaction underpriced"):
+		typed = ErrTxUnderpriced
+	default:
+		return err
+	}
+	return fmt.Errorf("%w: %w", typed, err)
+}
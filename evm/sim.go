@@ -4,17 +4,27 @@ import (
 	"context"
 	"crypto/ecdsa"
 	"errors"
+	"fmt"
 	"math/big"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/ethereum/go-ethereum/rpc"
 )
 
+// ErrBlockBeyondHead is returned when a call targets a block the sim has not committed yet.
+var ErrBlockBeyondHead = errors.New("evm: block number is beyond the simulated chain head")
+
+// ErrSimReset is delivered on Err() of head subscriptions that were open when Reset ran.
+var ErrSimReset = errors.New("evm: simulated chain was reset")
+
 // SimulatedBlockchainClient is a deterministic in-memory chain for tests/CI.
 // It uses go-evm's ethclient/simulated backend. :contentReference[oaicite:2]{index=2}
 //
@@ -29,6 +39,13 @@ type SimulatedBlockchainClient struct {
 	eventLogChannelMapMutex *sync.Mutex
 
 	chainID *big.Int
+
+	// Captured so Reset can rebuild the chain from scratch.
+	genesisAlloc types.GenesisAlloc
+	opts         SimOptions
+
+	headSubsMu sync.Mutex
+	headSubs   map[*pollingHeadSub]struct{}
 }
 
 var _ BlockchainClient = (*SimulatedBlockchainClient)(nil)
@@ -44,10 +61,7 @@ func NewSimulatedBlockchainClient(genesisAlloc types.GenesisAlloc, opts SimOptio
 		opts.BlockGasLimit = 100_000_000
 	}
 
-	b := simulated.NewBackend(
-		genesisAlloc,
-		simulated.WithBlockGasLimit(opts.BlockGasLimit),
-	)
+	b := newSimBackend(genesisAlloc, opts)
 
 	return &SimulatedBlockchainClient{
 		backend:                 b,
@@ -55,9 +69,19 @@ func NewSimulatedBlockchainClient(genesisAlloc types.GenesisAlloc, opts SimOptio
 		eventLogChannelMap:      make(map[string][]chan<- types.Log, 10),
 		eventLogChannelMapMutex: &sync.Mutex{},
 		chainID:                 big.NewInt(1337),
+		genesisAlloc:            genesisAlloc,
+		opts:                    opts,
+		headSubs:                make(map[*pollingHeadSub]struct{}),
 	}
 }
 
+func newSimBackend(genesisAlloc types.GenesisAlloc, opts SimOptions) *simulated.Backend {
+	return simulated.NewBackend(
+		genesisAlloc,
+		simulated.WithBlockGasLimit(opts.BlockGasLimit),
+	)
+}
+
 // NewSimulatedBlockchainClientWithAutoKey generates a key, funds it in genesis,
 // and returns the client + keypair for convenience.
 func NewSimulatedBlockchainClientWithAutoKey(initialBalanceWei *big.Int, opts SimOptions) (*SimulatedBlockchainClient, *ecdsa.PrivateKey, common.Address, error) {
@@ -104,6 +128,85 @@ func (c *SimulatedBlockchainClient) Fork(parent common.Hash) error {
 	return c.backend.Fork(parent)
 }
 
+// Reset rebuilds the chain from the genesis alloc and options given at construction,
+// so accounts funded in genesis (including the NewSimulatedBlockchainClientWithAutoKey key)
+// keep working. Open head subscriptions end with ErrSimReset; log subscriptions end with
+// the old backend. Not safe to call concurrently with other methods on c.
+func (c *SimulatedBlockchainClient) Reset() error {
+	c.headSubsMu.Lock()
+	for sub := range c.headSubs {
+		sub.terminate()
+	}
+	c.headSubs = make(map[*pollingHeadSub]struct{})
+	c.headSubsMu.Unlock()
+
+	var closeErr error
+	if c.backend != nil {
+		closeErr = c.backend.Close()
+	}
+
+	b := newSimBackend(c.genesisAlloc, c.opts)
+	c.backend = b
+	c.client = b.Client()
+
+	if closeErr != nil {
+		return fmt.Errorf("evm: close previous sim backend: %w", closeErr)
+	}
+	return nil
+}
+
+// WaitForTransaction is the sim's stand-in for bind.WaitMined, which would block forever
+// because nothing mines on its own here.
+//
+// Side effect: if txHash is still pending it calls Commit, sealing a block with every
+// pending tx in it, not just this one. An already mined tx returns its receipt without
+// committing; an unknown tx returns ethereum.NotFound.
+func (c *SimulatedBlockchainClient) WaitForTransaction(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	receipt, err := c.client.TransactionReceipt(ctx, txHash)
+	if err == nil {
+		return receipt, nil
+	}
+	if !errors.Is(err, ethereum.NotFound) {
+		return nil, err
+	}
+
+	_, pending, err := c.client.TransactionByHash(ctx, txHash)
+	if err != nil {
+		return nil, err
+	}
+	if pending {
+		c.Commit()
+	}
+	return c.client.TransactionReceipt(ctx, txHash)
+}
+
+// AssertBalance returns a descriptive error if addr's balance at the sim head isn't
+// expectedWei. It returns an error rather than failing a *testing.T so it works with
+// any test framework.
+func (c *SimulatedBlockchainClient) AssertBalance(ctx context.Context, addr common.Address, expectedWei *big.Int) error {
+	got, err := c.client.BalanceAt(ctx, addr, nil)
+	if err != nil {
+		return fmt.Errorf("evm: balance of %s: %w", addr.Hex(), err)
+	}
+	if got.Cmp(expectedWei) != 0 {
+		return fmt.Errorf("evm: balance of %s = %s wei, want %s (diff %s)",
+			addr.Hex(), got, expectedWei, new(big.Int).Sub(got, expectedWei))
+	}
+	return nil
+}
+
+// AssertNonce is AssertBalance for the account nonce at the sim head.
+func (c *SimulatedBlockchainClient) AssertNonce(ctx context.Context, addr common.Address, expected uint64) error {
+	got, err := c.client.NonceAt(ctx, addr, nil)
+	if err != nil {
+		return fmt.Errorf("evm: nonce of %s: %w", addr.Hex(), err)
+	}
+	if got != expected {
+		return fmt.Errorf("evm: nonce of %s = %d, want %d", addr.Hex(), got, expected)
+	}
+	return nil
+}
+
 // --- BlockchainClient methods (mostly just forwarded to c.client) ---
 
 func (c *SimulatedBlockchainClient) TransactionByHash(ctx context.Context, hash common.Hash) (*types.Transaction, bool, error) {
@@ -118,6 +221,25 @@ func (c *SimulatedBlockchainClient) BalanceAt(ctx context.Context, account commo
 	return c.client.BalanceAt(ctx, account, blockNumber)
 }
 
+// GetBalances has no batching to do in-process; it just loops BalanceAt.
+func (c *SimulatedBlockchainClient) GetBalances(ctx context.Context, addresses []common.Address, blockTag BlockTag) (map[common.Address]*big.Int, error) {
+	number, err := blockTag.BlockNumber()
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[common.Address]*big.Int, len(addresses))
+	var errs []error
+	for _, addr := range addresses {
+		bal, err := c.client.BalanceAt(ctx, addr, number)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", addr.Hex(), err))
+			continue
+		}
+		out[addr] = bal
+	}
+	return out, errors.Join(errs...)
+}
+
 func (c *SimulatedBlockchainClient) NetworkID(ctx context.Context) (*big.Int, error) {
 	// Sim backend always chainID 1337; NetworkID can be same here.
 	return new(big.Int).Set(c.chainID), nil
@@ -139,8 +261,82 @@ func (c *SimulatedBlockchainClient) ChainID(ctx context.Context) (*big.Int, erro
 	return new(big.Int).Set(c.chainID), nil
 }
 
+// CallContract resolves nil/"latest" to the committed head and rejects blocks past it,
+// instead of handing the simulated backend a number it can't serve.
+// The "pending" tag is routed to PendingCallContract.
 func (c *SimulatedBlockchainClient) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
-	return c.client.CallContract(ctx, msg, blockNumber)
+	number, pending, err := c.resolveCallBlock(ctx, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	if pending {
+		return c.client.PendingCallContract(ctx, msg)
+	}
+	return c.client.CallContract(ctx, msg, number)
+}
+
+// CallABI packs method(args...) against abiJSON, calls it at the sim head and returns
+// the decoded outputs in ABI order. Reverts come back as *RevertError.
+func (c *SimulatedBlockchainClient) CallABI(ctx context.Context, to common.Address, abiJSON, method string, args ...interface{}) ([]interface{}, error) {
+	parsed, err := abi.JSON(strings.NewReader(abiJSON))
+	if err != nil {
+		return nil, fmt.Errorf("evm: parse ABI: %w", err)
+	}
+	input, err := parsed.Pack(method, args...)
+	if err != nil {
+		return nil, fmt.Errorf("evm: pack %s: %w", method, err)
+	}
+
+	out, err := c.CallContract(ctx, ethereum.CallMsg{To: &to, Data: input}, nil)
+	if err != nil {
+		if revertErr := revertErrorFrom(err); revertErr != nil {
+			return nil, revertErr
+		}
+		return nil, fmt.Errorf("evm: call %s: %w", method, err)
+	}
+
+	values, err := parsed.Unpack(method, out)
+	if err != nil {
+		return nil, fmt.Errorf("evm: unpack %s: %w", method, err)
+	}
+	return values, nil
+}
+
+// resolveCallBlock maps a live-client style block argument onto the sim's committed chain.
+// Returns pending=true when the call should run against uncommitted state.
+func (c *SimulatedBlockchainClient) resolveCallBlock(ctx context.Context, blockNumber *big.Int) (*big.Int, bool, error) {
+	if blockNumber != nil && blockNumber.Sign() < 0 {
+		if !blockNumber.IsInt64() {
+			return nil, false, fmt.Errorf("evm: unsupported block tag %s", blockNumber)
+		}
+		switch rpc.BlockNumber(blockNumber.Int64()) {
+		case rpc.PendingBlockNumber:
+			return nil, true, nil
+		case rpc.LatestBlockNumber, rpc.SafeBlockNumber, rpc.FinalizedBlockNumber:
+			// Sim blocks are final as soon as they're committed.
+			blockNumber = nil
+		case rpc.EarliestBlockNumber:
+			return new(big.Int), false, nil
+		default:
+			return nil, false, fmt.Errorf("evm: unsupported block tag %s", blockNumber)
+		}
+	}
+
+	head, err := c.client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, false, fmt.Errorf("evm: read sim head: %w", err)
+	}
+	if head == nil || head.Number == nil {
+		return nil, false, errors.New("evm: sim head unavailable")
+	}
+
+	if blockNumber == nil {
+		return new(big.Int).Set(head.Number), false, nil
+	}
+	if blockNumber.Cmp(head.Number) > 0 {
+		return nil, false, fmt.Errorf("%w: requested %s, head %s", ErrBlockBeyondHead, blockNumber, head.Number)
+	}
+	return blockNumber, false, nil
 }
 
 func (c *SimulatedBlockchainClient) BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error) {
@@ -151,10 +347,64 @@ func (c *SimulatedBlockchainClient) TransactionCount(ctx context.Context, hash c
 	return c.client.TransactionCount(ctx, hash)
 }
 
+// GetTransactionByBlockAndIndex reads the committed block; "pending" is not supported.
+func (c *SimulatedBlockchainClient) GetTransactionByBlockAndIndex(ctx context.Context, blockTag BlockTag, index uint) (*BlockTransaction, error) {
+	block, err := c.blockForTag(ctx, blockTag)
+	if err != nil {
+		return nil, err
+	}
+
+	txs := block.Transactions()
+	if index >= uint(len(txs)) {
+		return nil, ethereum.NotFound
+	}
+	tx := txs[index]
+	from, err := types.Sender(types.LatestSignerForChainID(c.chainID), tx)
+	if err != nil {
+		return nil, fmt.Errorf("evm: recover sender: %w", err)
+	}
+
+	return &BlockTransaction{
+		Tx:          tx,
+		From:        from,
+		BlockHash:   block.Hash(),
+		BlockNumber: block.NumberU64(),
+		Index:       index,
+	}, nil
+}
+
+func (c *SimulatedBlockchainClient) BlockTransactionCount(ctx context.Context, blockTag BlockTag) (uint, error) {
+	block, err := c.blockForTag(ctx, blockTag)
+	if err != nil {
+		return 0, err
+	}
+	return uint(len(block.Transactions())), nil
+}
+
+func (c *SimulatedBlockchainClient) blockForTag(ctx context.Context, blockTag BlockTag) (*types.Block, error) {
+	tagged, err := blockTag.BlockNumber()
+	if err != nil {
+		return nil, err
+	}
+	number, pending, err := c.resolveCallBlock(ctx, tagged)
+	if err != nil {
+		return nil, err
+	}
+	if pending {
+		return nil, errors.New("evm: pending block is not available on the simulated chain")
+	}
+	return c.client.BlockByNumber(ctx, number)
+}
+
 func (c *SimulatedBlockchainClient) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
 	return c.client.HeaderByNumber(ctx, number)
 }
 
+// Syncing always returns nil: the sim is its own (and only) node.
+func (c *SimulatedBlockchainClient) Syncing(ctx context.Context) (*SyncStatus, error) {
+	return nil, nil
+}
+
 // --- bind.ContractCaller / Transactor / Filterer ---
 
 func (c *SimulatedBlockchainClient) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
@@ -165,6 +415,7 @@ func (c *SimulatedBlockchainClient) PendingCodeAt(ctx context.Context, account c
 	return c.client.PendingCodeAt(ctx, account)
 }
 
+// PendingCallContract runs the call against uncommitted (pending) sim state.
 func (c *SimulatedBlockchainClient) PendingCallContract(ctx context.Context, call ethereum.CallMsg) ([]byte, error) {
 	return c.client.PendingCallContract(ctx, call)
 }
@@ -197,7 +448,18 @@ func (c *SimulatedBlockchainClient) SubscribeNewHead(ctx context.Context, ch cha
 	if ch == nil {
 		return nil, errors.New("SubscribeNewHead: nil channel")
 	}
-	return newPollingHeadSub(ctx, c.client, ch, 250*time.Millisecond), nil
+	sub := newPollingHeadSub(ctx, c.client, ch, 250*time.Millisecond, c.forgetHeadSub)
+
+	c.headSubsMu.Lock()
+	c.headSubs[sub] = struct{}{}
+	c.headSubsMu.Unlock()
+	return sub, nil
+}
+
+func (c *SimulatedBlockchainClient) forgetHeadSub(sub *pollingHeadSub) {
+	c.headSubsMu.Lock()
+	delete(c.headSubs, sub)
+	c.headSubsMu.Unlock()
 }
 
 // ---- polling subscription implementation ----
@@ -206,18 +468,25 @@ type pollingHeadSub struct {
 	errCh  chan error
 	cancel context.CancelFunc
 	once   sync.Once
+
+	reset     chan struct{}
+	resetOnce sync.Once
 }
 
-func newPollingHeadSub(ctx context.Context, cli simulated.Client, out chan<- *types.Header, every time.Duration) ethereum.Subscription {
+func newPollingHeadSub(ctx context.Context, cli simulated.Client, out chan<- *types.Header, every time.Duration, onExit func(*pollingHeadSub)) *pollingHeadSub {
 	subCtx, cancel := context.WithCancel(ctx)
 
 	s := &pollingHeadSub{
 		errCh:  make(chan error, 1),
 		cancel: cancel,
+		reset:  make(chan struct{}),
 	}
 
 	go func() {
 		defer close(s.errCh)
+		if onExit != nil {
+			defer onExit(s)
+		}
 
 		t := time.NewTicker(every)
 		defer t.Stop()
@@ -229,6 +498,9 @@ func newPollingHeadSub(ctx context.Context, cli simulated.Client, out chan<- *ty
 			select {
 			case <-subCtx.Done():
 				return
+			case <-s.reset:
+				s.errCh <- ErrSimReset
+				return
 			case <-t.C:
 				h, err := cli.HeaderByNumber(subCtx, nil)
 				if err != nil {
@@ -264,3 +536,8 @@ func (s *pollingHeadSub) Unsubscribe() {
 func (s *pollingHeadSub) Err() <-chan error {
 	return s.errCh
 }
+
+// terminate ends the subscription with ErrSimReset.
+func (s *pollingHeadSub) terminate() {
+	s.resetOnce.Do(func() { close(s.reset) })
+}
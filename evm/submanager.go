@@ -0,0 +1,141 @@
+package evm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ErrSubscriptionManagerClosed is returned by AddSubscription after Close.
+var ErrSubscriptionManagerClosed = errors.New("evm: subscription manager closed")
+
+// SubscriptionID identifies a subscription added to a SubscriptionManager.
+type SubscriptionID uint64
+
+// SubscriptionManager runs many log subscriptions over one client, so they share its
+// single WS connection instead of each dialing their own. When the connection drops,
+// every subscription is re-established independently with backoff and backfilled via
+// eth_getLogs from its own position, as WatchEvent does.
+//
+// Drops are reported on the shared Err channel; they are informational, as recovery is
+// automatic. Close tears down every subscription and closes their channels; call it
+// before closing the client.
+type SubscriptionManager struct {
+	client BlockchainClient
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu     sync.Mutex
+	subs   map[SubscriptionID]context.CancelFunc
+	nextID SubscriptionID
+	closed bool
+
+	errCh chan error
+}
+
+func NewSubscriptionManager(client BlockchainClient) *SubscriptionManager {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &SubscriptionManager{
+		client: client,
+		ctx:    ctx,
+		cancel: cancel,
+		subs:   make(map[SubscriptionID]context.CancelFunc),
+		errCh:  make(chan error, 16),
+	}
+}
+
+// AddSubscription subscribes to logs matching query. The initial subscribe happens
+// before it returns, so a bad query fails here. The channel is closed after
+// RemoveSubscription or Close.
+func (m *SubscriptionManager) AddSubscription(ctx context.Context, query ethereum.FilterQuery) (SubscriptionID, <-chan types.Log, error) {
+	m.mu.Lock()
+	closed := m.closed
+	m.mu.Unlock()
+	if closed {
+		return 0, nil, ErrSubscriptionManagerClosed
+	}
+
+	// Subscribe without holding mu, so a slow node doesn't block RemoveSubscription or Close.
+	f, sub, logs, err := startLogFollower(ctx, m.client, query)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		sub.Unsubscribe()
+		return 0, nil, ErrSubscriptionManagerClosed
+	}
+
+	m.nextID++
+	id := m.nextID
+	subCtx, cancel := context.WithCancel(m.ctx)
+	m.subs[id] = cancel
+
+	out := make(chan types.Log, 128)
+	f.emit = func(ctx context.Context, l types.Log) bool {
+		select {
+		case out <- l:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+	f.onDrop = func(err error) { m.reportErr(fmt.Errorf("evm: subscription %d dropped: %w", id, err)) }
+
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		defer close(out)
+		f.run(subCtx, sub, logs)
+	}()
+	return id, out, nil
+}
+
+// RemoveSubscription stops the subscription and closes its channel. Unknown ids are ignored.
+func (m *SubscriptionManager) RemoveSubscription(id SubscriptionID) {
+	m.mu.Lock()
+	cancel, ok := m.subs[id]
+	delete(m.subs, id)
+	m.mu.Unlock()
+	if ok {
+		cancel()
+	}
+}
+
+// Err reports subscription drops (each is resubscribed automatically). It is closed by Close.
+// Errors are dropped rather than blocking when nobody is reading.
+func (m *SubscriptionManager) Err() <-chan error {
+	return m.errCh
+}
+
+// Close stops every subscription, waits for them to wind down and closes Err.
+func (m *SubscriptionManager) Close() error {
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return nil
+	}
+	m.closed = true
+	m.subs = make(map[SubscriptionID]context.CancelFunc)
+	m.mu.Unlock()
+
+	m.cancel()
+	m.wg.Wait()
+	close(m.errCh)
+	return nil
+}
+
+func (m *SubscriptionManager) reportErr(err error) {
+	select {
+	case m.errCh <- err:
+	default:
+	}
+}
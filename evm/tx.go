@@ -0,0 +1,225 @@
+package evm
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+type FeeStrategy int
+
+const (
+	// FeeStrategyAuto uses EIP-1559 when the chain reports a base fee, legacy otherwise.
+	FeeStrategyAuto FeeStrategy = iota
+	FeeStrategyLegacy
+	FeeStrategyEIP1559
+)
+
+// NonceSource hands out nonces for an account (e.g. a local nonce manager).
+// When nil, SendTx uses PendingNonceAt.
+type NonceSource interface {
+	NextNonce(ctx context.Context, account common.Address) (uint64, error)
+}
+
+type TxRequest struct {
+	From  *ecdsa.PrivateKey
+	To    *common.Address // nil for contract creation
+	Value *big.Int
+	Data  []byte
+
+	FeeStrategy FeeStrategy
+	GasLimit    uint64 // 0 = EstimateGasSafe
+
+	// Nonce selection: explicit Nonce wins, then NonceSource, then PendingNonceAt.
+	Nonce       *uint64
+	NonceSource NonceSource
+
+	// EIP-4844: a non-nil BlobSidecar sends a type-3 blob transaction. It needs a To
+	// address and EIP-1559 fees. MaxFeePerBlobGas defaults to 2x SuggestBlobFee.
+	BlobSidecar      *types.BlobTxSidecar
+	MaxFeePerBlobGas *big.Int
+}
+
+type TxResult struct {
+	Hash  common.Hash
+	Tx    *types.Transaction
+	Nonce uint64
+}
+
+// SendTx fills nonce, fees and gas for req, signs it with req.From and broadcasts it.
+func SendTx(ctx context.Context, client BlockchainClient, req TxRequest) (*TxResult, error) {
+	if client == nil {
+		return nil, errors.New("evm: nil client")
+	}
+	if req.From == nil {
+		return nil, errors.New("evm: TxRequest.From is required")
+	}
+
+	if req.BlobSidecar != nil {
+		if req.To == nil {
+			return nil, errors.New("evm: blob transactions cannot create contracts")
+		}
+		if req.FeeStrategy == FeeStrategyLegacy {
+			return nil, errors.New("evm: blob transactions need EIP-1559 fees")
+		}
+	}
+
+	from := crypto.PubkeyToAddress(req.From.PublicKey)
+	value := req.Value
+	if value == nil {
+		value = new(big.Int)
+	}
+
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("evm: chain id: %w", err)
+	}
+
+	nonce, err := resolveNonce(ctx, client, req, from)
+	if err != nil {
+		return nil, err
+	}
+
+	fees, err := resolveFees(ctx, client, req.FeeStrategy)
+	if err != nil {
+		return nil, err
+	}
+	if req.BlobSidecar != nil && !fees.dynamic {
+		return nil, ErrBlobsUnsupported
+	}
+
+	gasLimit := req.GasLimit
+	if gasLimit == 0 {
+		msg := ethereum.CallMsg{
+			From:  from,
+			To:    req.To,
+			Value: value,
+			Data:  req.Data,
+		}
+		if fees.dynamic {
+			msg.GasFeeCap = fees.feeCap
+			msg.GasTipCap = fees.tipCap
+		} else {
+			msg.GasPrice = fees.gasPrice
+		}
+		if req.BlobSidecar != nil {
+			msg.BlobHashes = req.BlobSidecar.BlobHashes()
+			msg.BlobGasFeeCap = req.MaxFeePerBlobGas
+		}
+		gasLimit, err = EstimateGasSafe(ctx, client, msg)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var txData types.TxData
+	if req.BlobSidecar != nil {
+		txData, err = blobTxData(ctx, client, req, chainID, nonce, fees, gasLimit, value)
+		if err != nil {
+			return nil, err
+		}
+	} else if fees.dynamic {
+		txData = &types.DynamicFeeTx{
+			ChainID:   chainID,
+			Nonce:     nonce,
+			GasTipCap: fees.tipCap,
+			GasFeeCap: fees.feeCap,
+			Gas:       gasLimit,
+			To:        req.To,
+			Value:     value,
+			Data:      req.Data,
+		}
+	} else {
+		txData = &types.LegacyTx{
+			Nonce:    nonce,
+			GasPrice: fees.gasPrice,
+			Gas:      gasLimit,
+			To:       req.To,
+			Value:    value,
+			Data:     req.Data,
+		}
+	}
+
+	signed, err := types.SignTx(types.NewTx(txData), types.LatestSignerForChainID(chainID), req.From)
+	if err != nil {
+		return nil, fmt.Errorf("evm: sign tx: %w", err)
+	}
+
+	if err := client.SendTransaction(ctx, signed); err != nil {
+		return nil, fmt.Errorf("evm: send tx: %w", ClassifySendError(err))
+	}
+
+	return &TxResult{
+		Hash:  signed.Hash(),
+		Tx:    signed,
+		Nonce: nonce,
+	}, nil
+}
+
+func resolveNonce(ctx context.Context, client BlockchainClient, req TxRequest, from common.Address) (uint64, error) {
+	if req.Nonce != nil {
+		return *req.Nonce, nil
+	}
+	if req.NonceSource != nil {
+		n, err := req.NonceSource.NextNonce(ctx, from)
+		if err != nil {
+			return 0, fmt.Errorf("evm: nonce source: %w", err)
+		}
+		return n, nil
+	}
+	n, err := client.PendingNonceAt(ctx, from)
+	if err != nil {
+		return 0, fmt.Errorf("evm: pending nonce: %w", err)
+	}
+	return n, nil
+}
+
+type txFees struct {
+	dynamic  bool
+	gasPrice *big.Int
+	tipCap   *big.Int
+	feeCap   *big.Int
+}
+
+func resolveFees(ctx context.Context, client BlockchainClient, strategy FeeStrategy) (*txFees, error) {
+	switch strategy {
+	case FeeStrategyLegacy:
+		return legacyFees(ctx, client)
+	case FeeStrategyEIP1559, FeeStrategyAuto:
+		head, err := client.HeaderByNumber(ctx, nil)
+		if err != nil {
+			return nil, fmt.Errorf("evm: read head: %w", err)
+		}
+		if head.BaseFee == nil {
+			if strategy == FeeStrategyEIP1559 {
+				return nil, errors.New("evm: chain does not support EIP-1559 (no base fee)")
+			}
+			return legacyFees(ctx, client)
+		}
+		tip, err := client.SuggestGasTipCap(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("evm: suggest gas tip cap: %w", err)
+		}
+		// 2x base fee leaves headroom for several full blocks of base fee growth.
+		feeCap := new(big.Int).Mul(head.BaseFee, big.NewInt(2))
+		feeCap.Add(feeCap, tip)
+		return &txFees{dynamic: true, tipCap: tip, feeCap: feeCap}, nil
+	default:
+		return nil, fmt.Errorf("evm: unknown fee strategy %d", strategy)
+	}
+}
+
+func legacyFees(ctx context.Context, client BlockchainClient) (*txFees, error) {
+	price, err := client.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("evm: suggest gas price: %w", err)
+	}
+	return &txFees{gasPrice: price}, nil
+}
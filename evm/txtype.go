@@ -0,0 +1,46 @@
+package evm
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// TransactionType returns tx's EIP-2718 type: types.LegacyTxType (0),
+// AccessListTxType (1), DynamicFeeTxType (2), BlobTxType (3) or SetCodeTxType (4).
+func TransactionType(tx *types.Transaction) uint8 {
+	return tx.Type()
+}
+
+// NormalizedFees returns the fee fields that are meaningful for tx's type, leaving the
+// others nil: legacy and access-list transactions have only gasPrice, while dynamic-fee,
+// blob and set-code transactions have maxFee (fee cap) and maxPriority (tip cap).
+//
+// go-ethereum's GasPrice() returns the fee cap for EIP-1559 transactions, which is an
+// upper bound rather than the price paid; use EffectiveGasPrice for that.
+func NormalizedFees(tx *types.Transaction) (maxFee, maxPriority, gasPrice *big.Int) {
+	switch tx.Type() {
+	case types.LegacyTxType, types.AccessListTxType:
+		return nil, nil, tx.GasPrice()
+	default:
+		return tx.GasFeeCap(), tx.GasTipCap(), nil
+	}
+}
+
+// EffectiveGasPrice returns the per-gas price tx pays in a block with baseFee:
+// min(feeCap, baseFee+tipCap) for EIP-1559 style transactions, gasPrice otherwise.
+// A nil baseFee (pre-London) yields the gas price or fee cap.
+func EffectiveGasPrice(tx *types.Transaction, baseFee *big.Int) *big.Int {
+	maxFee, maxPriority, gasPrice := NormalizedFees(tx)
+	if gasPrice != nil {
+		return gasPrice
+	}
+	if baseFee == nil {
+		return maxFee
+	}
+	price := new(big.Int).Add(baseFee, maxPriority)
+	if price.Cmp(maxFee) > 0 {
+		return new(big.Int).Set(maxFee)
+	}
+	return price
+}
@@ -0,0 +1,310 @@
+package evm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+const (
+	watchResubscribeMin = 1 * time.Second
+	watchResubscribeMax = 30 * time.Second
+
+	// A backfill re-reads this many blocks below the last delivered one, so logs
+	// re-included by a shallow reorg during a disconnect aren't missed.
+	watchReorgWindow = 64
+)
+
+// DecodedEvent is one contract log decoded against its ABI event.
+type DecodedEvent struct {
+	Name        string
+	Fields      map[string]interface{} // indexed and non-indexed arguments by name
+	Address     common.Address
+	BlockNumber uint64
+	BlockHash   common.Hash
+	TxHash      common.Hash
+	LogIndex    uint
+	Removed     bool // the log was reorged out
+	Raw         types.Log
+
+	// DecodeErr is set (and Fields is nil) when the log matched topic0 but its data
+	// didn't decode against the ABI, e.g. a contract emitting a same-signature event.
+	DecodeErr error
+}
+
+// WatchEvent subscribes to eventName logs from address and delivers them decoded.
+//
+// The subscription survives disconnects: when it drops, WatchEvent resubscribes with
+// backoff and backfills via eth_getLogs from shortly before the last block it delivered
+// (or from the head at subscribe time, if nothing was delivered yet), so nothing is
+// missed. Logs already delivered are not repeated; a log that was reorged out and
+// replaced is. Events stop and the channel is closed when ctx ends or Unsubscribe is called.
+func WatchEvent(ctx context.Context, client BlockchainClient, address common.Address, abiJSON, eventName string) (<-chan DecodedEvent, ethereum.Subscription, error) {
+	parsed, err := abi.JSON(strings.NewReader(abiJSON))
+	if err != nil {
+		return nil, nil, fmt.Errorf("evm: parse ABI: %w", err)
+	}
+	event, ok := parsed.Events[eventName]
+	if !ok {
+		return nil, nil, fmt.Errorf("evm: event %q not in ABI", eventName)
+	}
+	if event.Anonymous {
+		return nil, nil, fmt.Errorf("evm: anonymous event %q has no topic to filter on", eventName)
+	}
+
+	query := ethereum.FilterQuery{
+		Addresses: []common.Address{address},
+		Topics:    [][]common.Hash{{event.ID}},
+	}
+
+	f, sub, logs, err := startLogFollower(ctx, client, query)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	watchCtx, cancel := context.WithCancel(ctx)
+	w := &eventWatcher{
+		event:  event,
+		out:    make(chan DecodedEvent, 128),
+		errCh:  make(chan error, 1),
+		cancel: cancel,
+	}
+	f.emit = w.emit
+	go func() {
+		defer close(w.out)
+		defer close(w.errCh)
+		f.run(watchCtx, sub, logs)
+	}()
+	return w.out, w, nil
+}
+
+type eventWatcher struct {
+	event abi.Event
+
+	out    chan DecodedEvent
+	errCh  chan error
+	cancel context.CancelFunc
+	once   sync.Once
+}
+
+func (w *eventWatcher) Unsubscribe() {
+	w.once.Do(func() { w.cancel() })
+}
+
+func (w *eventWatcher) Err() <-chan error {
+	return w.errCh
+}
+
+func (w *eventWatcher) emit(ctx context.Context, l types.Log) bool {
+	select {
+	case w.out <- w.decode(l):
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// logFollower keeps a log subscription alive: when it drops, it resubscribes with backoff
+// and backfills via eth_getLogs, skipping logs it already emitted. It is shared by
+// WatchEvent and SubscriptionManager.
+type logFollower struct {
+	client BlockchainClient
+	query  ethereum.FilterQuery
+	emit   func(ctx context.Context, l types.Log) bool // false if ctx ended
+	onDrop func(err error)                             // optional; called when the subscription fails
+
+	// Backfill position. floor is the head when the subscription started, so a drop
+	// before the first log still backfills; lastBlock is the highest delivered block.
+	floor     uint64
+	lastBlock uint64
+
+	// Delivered logs, by block hash rather than number so a reorg's replacement logs at
+	// the same height are not mistaken for duplicates. Pruned below the backfill start.
+	seen map[logKey]uint64
+}
+
+type logKey struct {
+	blockHash common.Hash
+	index     uint
+}
+
+// startLogFollower records the current head and then subscribes, so everything from
+// the head on is covered by the subscription or a later backfill. The caller sets emit
+// (and onDrop) before running it.
+func startLogFollower(ctx context.Context, client BlockchainClient, query ethereum.FilterQuery) (*logFollower, ethereum.Subscription, chan types.Log, error) {
+	head, err := client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("evm: read head before subscribing: %w", err)
+	}
+
+	logs := make(chan types.Log, 128)
+	sub, err := client.SubscribeFilterLogs(ctx, query, logs)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("evm: subscribe logs: %w", err)
+	}
+
+	floor := head.Number.Uint64()
+	return &logFollower{
+		client:    client,
+		query:     query,
+		floor:     floor,
+		lastBlock: floor,
+	}, sub, logs, nil
+}
+
+// run follows the subscription until ctx ends.
+func (f *logFollower) run(ctx context.Context, sub ethereum.Subscription, logs chan types.Log) {
+	backoff := watchResubscribeMin
+	for {
+		err := f.pump(ctx, sub, logs)
+		sub.Unsubscribe()
+		if err == nil || ctx.Err() != nil {
+			return
+		}
+		if f.onDrop != nil {
+			f.onDrop(err)
+		}
+
+		// Resubscribe first, then backfill, so logs emitted in between land on the new subscription.
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(backoff):
+			}
+
+			logs = make(chan types.Log, 128)
+			sub, err = f.client.SubscribeFilterLogs(ctx, f.query, logs)
+			if err == nil {
+				if err = f.backfill(ctx); err == nil {
+					break
+				}
+				sub.Unsubscribe()
+			}
+			backoff = min(backoff*2, watchResubscribeMax)
+		}
+		backoff = watchResubscribeMin
+	}
+}
+
+// pump forwards logs until the subscription fails (returns its error) or ctx ends (returns nil).
+func (f *logFollower) pump(ctx context.Context, sub ethereum.Subscription, logs <-chan types.Log) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err, ok := <-sub.Err():
+			if !ok || err == nil {
+				err = errors.New("evm: log subscription closed")
+			}
+			return err
+		case l := <-logs:
+			if !f.deliver(ctx, l) {
+				return nil
+			}
+		}
+	}
+}
+
+// backfillStart is the first block a backfill reads: watchReorgWindow blocks below the
+// last delivered one, but never before the subscription started.
+func (f *logFollower) backfillStart() uint64 {
+	if f.lastBlock < f.floor+watchReorgWindow {
+		return f.floor
+	}
+	return f.lastBlock - watchReorgWindow
+}
+
+// backfill replays logs from backfillStart up to the current head.
+func (f *logFollower) backfill(ctx context.Context) error {
+	q := f.query
+	q.FromBlock = new(big.Int).SetUint64(f.backfillStart())
+	missed, err := f.client.FilterLogs(ctx, q)
+	if err != nil {
+		return fmt.Errorf("evm: backfill logs: %w", err)
+	}
+	for _, l := range missed {
+		if !f.deliver(ctx, l) {
+			return nil
+		}
+	}
+	return nil
+}
+
+// deliver emits l unless it was already delivered. Returns false if ctx ended.
+func (f *logFollower) deliver(ctx context.Context, l types.Log) bool {
+	key := logKey{blockHash: l.BlockHash, index: l.Index}
+	if l.Removed {
+		// Forget the log, so it is delivered again if re-included in the same block, and
+		// make the next backfill rescan from its height.
+		delete(f.seen, key)
+		f.lastBlock = max(min(f.lastBlock, l.BlockNumber), f.floor)
+		return f.emit(ctx, l)
+	}
+
+	if _, dup := f.seen[key]; dup {
+		return true
+	}
+	if f.seen == nil {
+		f.seen = make(map[logKey]uint64)
+	}
+	f.seen[key] = l.BlockNumber
+	if l.BlockNumber > f.lastBlock {
+		f.lastBlock = l.BlockNumber
+		start := f.backfillStart()
+		for k, n := range f.seen {
+			if n < start {
+				delete(f.seen, k)
+			}
+		}
+	}
+	return f.emit(ctx, l)
+}
+
+func (w *eventWatcher) decode(l types.Log) DecodedEvent {
+	ev := DecodedEvent{
+		Name:        w.event.Name,
+		Address:     l.Address,
+		BlockNumber: l.BlockNumber,
+		BlockHash:   l.BlockHash,
+		TxHash:      l.TxHash,
+		LogIndex:    l.Index,
+		Removed:     l.Removed,
+		Raw:         l,
+	}
+
+	fields := make(map[string]interface{}, len(w.event.Inputs))
+	if len(l.Data) > 0 {
+		if err := w.event.Inputs.NonIndexed().UnpackIntoMap(fields, l.Data); err != nil {
+			ev.DecodeErr = fmt.Errorf("evm: decode %s data: %w", w.event.Name, err)
+			return ev
+		}
+	}
+
+	var indexed abi.Arguments
+	for _, arg := range w.event.Inputs {
+		if arg.Indexed {
+			indexed = append(indexed, arg)
+		}
+	}
+	if len(l.Topics) != len(indexed)+1 {
+		ev.DecodeErr = fmt.Errorf("evm: decode %s: got %d topics, want %d", w.event.Name, len(l.Topics), len(indexed)+1)
+		return ev
+	}
+	if err := abi.ParseTopicsIntoMap(fields, indexed, l.Topics[1:]); err != nil {
+		ev.DecodeErr = fmt.Errorf("evm: decode %s topics: %w", w.event.Name, err)
+		return ev
+	}
+
+	ev.Fields = fields
+	return ev
+}
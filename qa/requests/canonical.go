@@ -1,6 +1,8 @@
 package requests
 
 import (
+	"crypto/sha256"
+	"crypto/sha512"
 	"encoding/hex"
 	"fmt"
 	"net"
@@ -23,6 +25,10 @@ type CanonicalInput struct {
 	UserID      string
 	DeviceID    string
 
+	// BodyHashAlg selects the body digest algorithm; empty means BodyHashSHA256.
+	BodyHashAlg BodyHashAlg
+	// BodyHashHex is the hex body digest. For SHA-256, BodySHA256Hex is still accepted.
+	BodyHashHex   string
 	BodySHA256Hex string
 }
 
@@ -35,8 +41,49 @@ type ParsedCanonical struct {
 	ChallengeID string
 	UserID      string
 	DeviceID    string
-	BodySHA256  string
+	BodyHashAlg BodyHashAlg
+	BodyHash    string
+	BodySHA256  string // set only when BodyHashAlg is BodyHashSHA256
 }
+
+// BodyHashAlg names the hash used for the body digest line ("BODY-<alg>: <hex>").
+type BodyHashAlg string
+
+const (
+	BodyHashSHA256 BodyHashAlg = "SHA256"
+	BodyHashSHA384 BodyHashAlg = "SHA384"
+	BodyHashSHA512 BodyHashAlg = "SHA512"
+)
+
+// hexLen is the length of the hex digest for a supported algorithm, 0 otherwise.
+func (a BodyHashAlg) hexLen() int {
+	switch a {
+	case BodyHashSHA256:
+		return sha256.Size * 2
+	case BodyHashSHA384:
+		return sha512.Size384 * 2
+	case BodyHashSHA512:
+		return sha512.Size * 2
+	}
+	return 0
+}
+
+// HashBody returns the lowercase hex digest of body under alg (empty alg means SHA-256).
+func HashBody(alg BodyHashAlg, body []byte) (string, error) {
+	switch alg {
+	case "", BodyHashSHA256:
+		sum := sha256.Sum256(body)
+		return hex.EncodeToString(sum[:]), nil
+	case BodyHashSHA384:
+		sum := sha512.Sum384(body)
+		return hex.EncodeToString(sum[:]), nil
+	case BodyHashSHA512:
+		sum := sha512.Sum512(body)
+		return hex.EncodeToString(sum[:]), nil
+	}
+	return "", fmt.Errorf("unsupported body hash algorithm %q", alg)
+}
+
 type PathNormalizeOptions struct {
 	CollapseSlashes bool
 }
@@ -54,15 +101,27 @@ var allowedMethods = map[string]struct{}{
 }
 
 func CanonicalString(ci CanonicalInput) (string, error) {
-	bodyHex := strings.ToLower(strings.TrimSpace(ci.BodySHA256Hex))
+	alg := ci.BodyHashAlg
+	if alg == "" {
+		alg = BodyHashSHA256
+	}
+	if alg.hexLen() == 0 {
+		return "", fmt.Errorf("unsupported body hash algorithm %q", alg)
+	}
+
+	bodyHex := ci.BodyHashHex
+	if bodyHex == "" && alg == BodyHashSHA256 {
+		bodyHex = ci.BodySHA256Hex
+	}
+	bodyHex = strings.ToLower(strings.TrimSpace(bodyHex))
 	if bodyHex == "" {
-		return "", fmt.Errorf("missing body sha256")
+		return "", fmt.Errorf("missing body %s", strings.ToLower(string(alg)))
 	}
-	if len(bodyHex) != 64 {
-		return "", fmt.Errorf("invalid body sha256 length")
+	if len(bodyHex) != alg.hexLen() {
+		return "", fmt.Errorf("invalid body %s length", strings.ToLower(string(alg)))
 	}
 	if _, err := hex.DecodeString(bodyHex); err != nil {
-		return "", fmt.Errorf("invalid body sha256 hex")
+		return "", fmt.Errorf("invalid body %s hex", strings.ToLower(string(alg)))
 	}
 
 	aud := NormalizeBackendHost(ci.BackendHost)
@@ -79,15 +138,15 @@ func CanonicalString(ci CanonicalInput) (string, error) {
 		fmt.Sprintf("CHALLENGE: %s", ci.ChallengeID),
 		fmt.Sprintf("USER: %s", ci.UserID),
 		fmt.Sprintf("DEVICE: %s", ci.DeviceID),
-		fmt.Sprintf("BODY-SHA256: %s", bodyHex),
+		fmt.Sprintf("BODY-%s: %s", alg, bodyHex),
 	}, "\n"), nil
 }
 
 // ParseCanonicalString parses a canonical string back into fields.
 func ParseCanonicalString(s string) (*ParsedCanonical, error) {
 	lines := strings.Split(strings.TrimSpace(s), "\n")
-	if len(lines) != 8 {
-		return nil, fmt.Errorf("unexpected canonical line count: got %d, want 8", len(lines))
+	if len(lines) != 9 {
+		return nil, fmt.Errorf("unexpected canonical line count: got %d, want 9", len(lines))
 	}
 
 	out := &ParsedCanonical{
@@ -142,12 +201,21 @@ func ParseCanonicalString(s string) (*ParsedCanonical, error) {
 	}
 	out.DeviceID = strings.TrimSpace(strings.TrimPrefix(lines[7], devPrefix))
 
-	// BODY-SHA256
-	const bodyPrefix = "BODY-SHA256: "
-	if !strings.HasPrefix(lines[8], bodyPrefix) {
-		return nil, fmt.Errorf("invalid BODY-SHA256 line: %q", lines[8])
+	// BODY-<alg>
+	const bodyPrefix = "BODY-"
+	label, value, ok := strings.Cut(lines[8], ": ")
+	if !ok || !strings.HasPrefix(label, bodyPrefix) {
+		return nil, fmt.Errorf("invalid BODY line: %q", lines[8])
+	}
+	alg := BodyHashAlg(strings.TrimPrefix(label, bodyPrefix))
+	if alg.hexLen() == 0 {
+		return nil, fmt.Errorf("unsupported body hash algorithm %q", alg)
+	}
+	out.BodyHashAlg = alg
+	out.BodyHash = strings.TrimSpace(value)
+	if alg == BodyHashSHA256 {
+		out.BodySHA256 = out.BodyHash
 	}
-	out.BodySHA256 = strings.TrimSpace(strings.TrimPrefix(lines[8], bodyPrefix))
 
 	return out, nil
 }
@@ -0,0 +1,44 @@
+package requests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrReplayedChallenge    = errors.New("replayed challenge")
+	ErrTimestampOutOfWindow = errors.New("timestamp outside allowed skew window")
+)
+
+// ReplayGuard records challenge IDs that have been used.
+// Seen returns true if challengeID was already recorded, and records it otherwise.
+// redis.ReplayGuard is the Redis-backed implementation.
+type ReplayGuard interface {
+	Seen(ctx context.Context, challengeID string, ts int64) (bool, error)
+}
+
+// VerifyNotReplayed rejects ts (unix seconds) outside now±skew and any challenge the guard has already seen.
+func VerifyNotReplayed(ctx context.Context, guard ReplayGuard, challengeID string, ts int64, now time.Time, skew time.Duration) error {
+	if guard == nil {
+		return fmt.Errorf("missing replay guard")
+	}
+
+	diff := now.Sub(time.Unix(ts, 0))
+	if diff < 0 {
+		diff = -diff
+	}
+	if diff > skew {
+		return ErrTimestampOutOfWindow
+	}
+
+	seen, err := guard.Seen(ctx, challengeID, ts)
+	if err != nil {
+		return err
+	}
+	if seen {
+		return ErrReplayedChallenge
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Marshaler lets JSONClient use something other than encoding/json.
+type Marshaler interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+type stdJSONMarshaler struct{}
+
+func (stdJSONMarshaler) Marshal(v interface{}) ([]byte, error)      { return json.Marshal(v) }
+func (stdJSONMarshaler) Unmarshal(data []byte, v interface{}) error { return json.Unmarshal(data, v) }
+
+// JSONClient wraps a Redis client with marshal-on-set / unmarshal-on-get helpers.
+type JSONClient struct {
+	client    redis.Cmdable
+	marshaler Marshaler
+}
+
+// NewJSONClient uses encoding/json.
+func NewJSONClient(client redis.Cmdable) *JSONClient {
+	return NewJSONClientWithMarshaler(client, nil)
+}
+
+// NewJSONClientWithMarshaler uses m, or encoding/json if m is nil.
+func NewJSONClientWithMarshaler(client redis.Cmdable, m Marshaler) *JSONClient {
+	if m == nil {
+		m = stdJSONMarshaler{}
+	}
+	return &JSONClient{client: client, marshaler: m}
+}
+
+// SetJSON marshals v and stores it under key. A ttl of 0 means no expiry.
+func (c *JSONClient) SetJSON(ctx context.Context, key string, v interface{}, ttl time.Duration) error {
+	b, err := c.marshaler.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("redis: marshal %s: %w", key, err)
+	}
+	return c.client.Set(ctx, key, b, ttl).Err()
+}
+
+// GetJSON loads key into dest. A missing key returns found=false and no error.
+func (c *JSONClient) GetJSON(ctx context.Context, key string, dest interface{}) (bool, error) {
+	b, err := c.client.Get(ctx, key).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return false, nil
+		}
+		return false, err
+	}
+	if err := c.marshaler.Unmarshal(b, dest); err != nil {
+		return false, fmt.Errorf("redis: unmarshal %s: %w", key, err)
+	}
+	return true, nil
+}
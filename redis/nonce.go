@@ -2,6 +2,7 @@ package redis
 
 import (
 	"context"
+	"time"
 
 	"github.com/redis/go-redis/v9"
 )
@@ -31,3 +32,9 @@ func EnsureMonotonic(ctx context.Context, r redis.Scripter, key string, value in
 	}
 	return res == 1, nil
 }
+
+// SetOnce stores value under key with ttl only if key does not exist yet.
+// Returns true if stored, false if the key was already present (replay).
+func SetOnce(ctx context.Context, r redis.Cmdable, key string, value interface{}, ttl time.Duration) (bool, error) {
+	return r.SetNX(ctx, key, value, ttl).Result()
+}
@@ -0,0 +1,44 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ReplayGuard records used challenge IDs in Redis with SET NX and a TTL. It implements
+// requests.ReplayGuard from qa/requests.
+type ReplayGuard struct {
+	client redis.Cmdable
+	prefix string
+	ttl    time.Duration
+}
+
+// NewReplayGuard keeps challenge IDs for twice the allowed skew, which covers every
+// timestamp requests.VerifyNotReplayed would still accept. skew must be positive: a zero
+// TTL would store the IDs forever.
+func NewReplayGuard(client redis.Cmdable, skew time.Duration) (*ReplayGuard, error) {
+	if skew <= 0 {
+		return nil, fmt.Errorf("replay guard: skew must be positive, got %s", skew)
+	}
+	return &ReplayGuard{
+		client: client,
+		prefix: "qa:challenge:",
+		ttl:    2 * skew,
+	}, nil
+}
+
+// Seen reports whether challengeID was already recorded, and records it otherwise.
+func (g *ReplayGuard) Seen(ctx context.Context, challengeID string, ts int64) (bool, error) {
+	if challengeID == "" {
+		return false, fmt.Errorf("missing challenge id")
+	}
+	stored, err := SetOnce(ctx, g.client, g.prefix+challengeID, strconv.FormatInt(ts, 10), g.ttl)
+	if err != nil {
+		return false, fmt.Errorf("replay guard: %w", err)
+	}
+	return !stored, nil
+}
@@ -0,0 +1,139 @@
+package retry
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/quantumauth-io/quantum-go-utils/log"
+)
+
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+/*
+Breaker is an optional circuit breaker consulted by Retry when set on Config.
+
+After FailureThreshold consecutive failures for an operation (keyed by descriptionOfOperation)
+the circuit opens and calls fail fast with ErrCircuitOpen until Cooldown elapses. The circuit
+then goes half-open and lets a single trial call through: success closes it, failure re-opens it.
+
+A Breaker is safe for concurrent use and is meant to be shared across calls.
+*/
+type Breaker struct {
+	FailureThreshold int32
+	Cooldown         time.Duration
+
+	mu       sync.Mutex
+	circuits map[string]*circuit
+	now      func() time.Time
+}
+
+type circuit struct {
+	state               breakerState
+	consecutiveFailures int32
+	openedAt            time.Time
+	trialInFlight       bool
+}
+
+func NewBreaker(failureThreshold int32, cooldown time.Duration) *Breaker {
+	return &Breaker{
+		FailureThreshold: failureThreshold,
+		Cooldown:         cooldown,
+		circuits:         make(map[string]*circuit),
+		now:              time.Now,
+	}
+}
+
+// Allow reports whether a call for operation may proceed.
+func (b *Breaker) Allow(operation string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c := b.circuitLocked(operation)
+	switch c.state {
+	case breakerOpen:
+		if b.now().Sub(c.openedAt) < b.Cooldown {
+			return ErrCircuitOpen
+		}
+		c.state = breakerHalfOpen
+		c.trialInFlight = true
+		return nil
+	case breakerHalfOpen:
+		if c.trialInFlight {
+			return ErrCircuitOpen
+		}
+		c.trialInFlight = true
+		return nil
+	default:
+		return nil
+	}
+}
+
+// RecordSuccess closes the circuit for operation.
+func (b *Breaker) RecordSuccess(operation string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c := b.circuitLocked(operation)
+	if c.state != breakerClosed {
+		log.Info("Circuit closed", "operation", operation)
+	}
+	c.state = breakerClosed
+	c.consecutiveFailures = 0
+	c.trialInFlight = false
+}
+
+// RecordFailure counts a failure for operation and opens the circuit once the threshold is hit.
+func (b *Breaker) RecordFailure(operation string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c := b.circuitLocked(operation)
+	c.consecutiveFailures++
+	c.trialInFlight = false
+
+	if c.state == breakerHalfOpen ||
+		(c.state == breakerClosed && b.FailureThreshold > 0 && c.consecutiveFailures >= b.FailureThreshold) {
+		if c.state == breakerClosed {
+			log.Warn("Circuit opened", "operation", operation,
+				"consecutiveFailures", c.consecutiveFailures, "cooldown", b.Cooldown)
+		}
+		c.state = breakerOpen
+		c.openedAt = b.now()
+	}
+}
+
+// IsOpen reports whether the circuit for operation is currently failing fast.
+func (b *Breaker) IsOpen(operation string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c, ok := b.circuits[operation]
+	if !ok {
+		return false
+	}
+	return c.state == breakerOpen && b.now().Sub(c.openedAt) < b.Cooldown
+}
+
+func (b *Breaker) circuitLocked(operation string) *circuit {
+	if b.circuits == nil {
+		b.circuits = make(map[string]*circuit)
+	}
+	if b.now == nil {
+		b.now = time.Now
+	}
+	c, ok := b.circuits[operation]
+	if !ok {
+		c = &circuit{}
+		b.circuits[operation] = c
+	}
+	return c
+}
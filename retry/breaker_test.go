@@ -0,0 +1,196 @@
+package retry
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// fakeClock is a settable clock for Breaker.now.
+type fakeClock struct {
+	mu sync.Mutex
+	t  time.Time
+}
+
+func (c *fakeClock) now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.t
+}
+
+func (c *fakeClock) advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.t = c.t.Add(d)
+}
+
+func newTestBreaker(threshold int32, cooldown time.Duration) (*Breaker, *fakeClock) {
+	clock := &fakeClock{t: time.Unix(1700000000, 0)}
+	b := NewBreaker(threshold, cooldown)
+	b.now = clock.now
+	return b, clock
+}
+
+func TestBreakerOpensAtThreshold(t *testing.T) {
+	b, _ := newTestBreaker(3, time.Minute)
+
+	for i := 1; i < 3; i++ {
+		b.RecordFailure("op")
+		if err := b.Allow("op"); err != nil {
+			t.Fatalf("after %d failures Allow = %v, want nil", i, err)
+		}
+	}
+	b.RecordFailure("op")
+	if err := b.Allow("op"); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("after 3 failures Allow = %v, want ErrCircuitOpen", err)
+	}
+	if !b.IsOpen("op") {
+		t.Fatal("IsOpen = false after reaching the threshold")
+	}
+}
+
+func TestBreakerSuccessResetsCount(t *testing.T) {
+	b, _ := newTestBreaker(2, time.Minute)
+
+	b.RecordFailure("op")
+	b.RecordSuccess("op")
+	b.RecordFailure("op")
+	if err := b.Allow("op"); err != nil {
+		t.Fatalf("Allow = %v, want nil: failures weren't consecutive", err)
+	}
+}
+
+func TestBreakerHalfOpenAfterCooldown(t *testing.T) {
+	b, clock := newTestBreaker(1, time.Minute)
+
+	b.RecordFailure("op")
+	clock.advance(time.Minute - time.Second)
+	if err := b.Allow("op"); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("before cooldown Allow = %v, want ErrCircuitOpen", err)
+	}
+
+	// After the cooldown a single trial goes through.
+	clock.advance(time.Second)
+	if err := b.Allow("op"); err != nil {
+		t.Fatalf("after cooldown Allow = %v, want nil", err)
+	}
+	if err := b.Allow("op"); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("second call while trial in flight Allow = %v, want ErrCircuitOpen", err)
+	}
+
+	// A failed trial re-opens the circuit for another full cooldown.
+	b.RecordFailure("op")
+	if err := b.Allow("op"); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("after failed trial Allow = %v, want ErrCircuitOpen", err)
+	}
+	clock.advance(time.Minute)
+	if err := b.Allow("op"); err != nil {
+		t.Fatalf("after second cooldown Allow = %v, want nil", err)
+	}
+
+	// A successful trial closes it.
+	b.RecordSuccess("op")
+	for i := 0; i < 3; i++ {
+		if err := b.Allow("op"); err != nil {
+			t.Fatalf("after successful trial Allow = %v, want nil", err)
+		}
+	}
+	if b.IsOpen("op") {
+		t.Fatal("IsOpen = true after a successful trial")
+	}
+}
+
+func TestBreakerKeysAreIsolated(t *testing.T) {
+	b, _ := newTestBreaker(2, time.Minute)
+
+	b.RecordFailure("a")
+	b.RecordFailure("a")
+	b.RecordFailure("b")
+	if err := b.Allow("a"); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("Allow(a) = %v, want ErrCircuitOpen", err)
+	}
+	if err := b.Allow("b"); err != nil {
+		t.Fatalf("Allow(b) = %v, want nil", err)
+	}
+	if err := b.Allow("c"); err != nil {
+		t.Fatalf("Allow(c) = %v, want nil", err)
+	}
+
+	b.RecordSuccess("b")
+	if !b.IsOpen("a") {
+		t.Fatal("success on b closed the circuit for a")
+	}
+}
+
+func TestRetryFailsFastWhenCircuitOpen(t *testing.T) {
+	b, _ := newTestBreaker(2, time.Minute)
+	cfg := DefaultConfig()
+	cfg.MaxNumRetries = 5
+	cfg.InitialDelayBeforeRetrying = time.Millisecond
+	cfg.MaxDelayBeforeRetrying = time.Millisecond
+	cfg.Breaker = b
+
+	var calls int
+	_, err := Retry(context.Background(), cfg, func(context.Context) ([]interface{}, error) {
+		calls++
+		return nil, fmt.Errorf("connection refused")
+	}, nil, "op")
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("Retry err = %v, want ErrCircuitOpen", err)
+	}
+	if calls != 2 {
+		t.Fatalf("operation ran %d times, want 2 (the threshold)", calls)
+	}
+}
+
+func TestBreakerConcurrentUse(t *testing.T) {
+	b, clock := newTestBreaker(5, time.Minute)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 16; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			op := fmt.Sprintf("op-%d", g%4)
+			for i := 0; i < 200; i++ {
+				if b.Allow(op) == nil {
+					if i%3 == 0 {
+						b.RecordSuccess(op)
+					} else {
+						b.RecordFailure(op)
+					}
+				}
+				_ = b.IsOpen(op)
+				if i%50 == 0 {
+					clock.advance(time.Minute)
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	// Once open, concurrent callers past the cooldown get exactly one trial.
+	for i := 0; i < 5; i++ {
+		b.RecordFailure("trial")
+	}
+	clock.advance(time.Minute)
+	var allowed atomic.Int32
+	for g := 0; g < 16; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if b.Allow("trial") == nil {
+				allowed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	if n := allowed.Load(); n != 1 {
+		t.Fatalf("%d concurrent trials allowed in half-open, want 1", n)
+	}
+}
@@ -0,0 +1,26 @@
+package retry
+
+import "github.com/pkg/errors"
+
+var (
+	// ErrRetriesExhausted marks a Retry that hit MaxNumRetries while the error was still retryable.
+	ErrRetriesExhausted = errors.New("retries exhausted")
+	// ErrUnretryable marks a Retry that stopped because shouldRetryFn rejected the error.
+	ErrUnretryable = errors.New("unretryable error")
+)
+
+// markedError tags an operation error with one of the sentinels above without changing
+// its message: errors.Is matches both the sentinel and the original error, and
+// errors.Cause still returns the original error.
+type markedError struct {
+	mark error
+	err  error
+}
+
+func (e *markedError) Error() string   { return e.err.Error() }
+func (e *markedError) Cause() error    { return e.err }
+func (e *markedError) Unwrap() []error { return []error{e.mark, e.err} }
+
+func mark(sentinel error, err error) error {
+	return &markedError{mark: sentinel, err: err}
+}
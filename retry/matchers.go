@@ -0,0 +1,99 @@
+package retry
+
+import (
+	"context"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"syscall"
+
+	"github.com/pkg/errors"
+)
+
+// RetryIf combines matchers into a shouldRetryFn for Retry: an error is retried if any
+// matcher accepts it. nil is never retried.
+//
+//	retry.Retry(ctx, cfg, fn, retry.RetryIf(retry.IsNetworkError, retry.IsTimeout), "op")
+func RetryIf(matchers ...func(error) bool) func(error) bool {
+	return func(err error) bool {
+		if err == nil {
+			return false
+		}
+		for _, match := range matchers {
+			if match(err) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// isContextErr reports a cancelled or expired context. Such errors must never match:
+// context.DeadlineExceeded implements Timeout() and Temporary(), and retrying once the
+// caller has given up can't succeed.
+func isContextErr(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// IsNetworkError matches connection-level failures: net.Error (except a DNS name that
+// doesn't exist), reset/refused/aborted/broken pipe, a connection closed mid-response
+// (io.ErrUnexpectedEOF, or a message ending in ": EOF"), and drivers that only report
+// these as text.
+func IsNetworkError(err error) bool {
+	if err == nil || isContextErr(err) {
+		return false
+	}
+
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+		return false
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	if errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNABORTED) ||
+		errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "connection reset by peer") ||
+		strings.Contains(msg, "broken pipe") ||
+		strings.Contains(msg, "connection refused") ||
+		strings.Contains(msg, "server closed the connection") ||
+		strings.Contains(msg, "use of closed network connection") ||
+		strings.HasSuffix(msg, ": eof")
+}
+
+// IsTimeout matches errors that report Timeout() (net.Error and friends), I/O deadlines,
+// and drivers that only report a timeout as text. It does not match context.DeadlineExceeded:
+// once the caller's deadline has passed, another attempt can't succeed.
+func IsTimeout(err error) bool {
+	if err == nil || isContextErr(err) {
+		return false
+	}
+
+	var t interface{ Timeout() bool }
+	if errors.As(err, &t) && t.Timeout() {
+		return true
+	}
+	if errors.Is(err, os.ErrDeadlineExceeded) {
+		return true
+	}
+	return strings.Contains(strings.ToLower(err.Error()), "timeout")
+}
+
+// IsTemporary matches errors that report Temporary() == true. Few standard library
+// errors still implement it, but some drivers and SDKs do. Context errors never match.
+func IsTemporary(err error) bool {
+	if err == nil || isContextErr(err) {
+		return false
+	}
+	var t interface{ Temporary() bool }
+	return errors.As(err, &t) && t.Temporary()
+}
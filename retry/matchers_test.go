@@ -0,0 +1,72 @@
+package retry
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"syscall"
+	"testing"
+)
+
+type tempErr struct{}
+
+func (tempErr) Error() string   { return "try again" }
+func (tempErr) Temporary() bool { return true }
+
+func TestMatchers(t *testing.T) {
+	dialErr := &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}
+	dnsNotFound := &net.DNSError{Err: "no such host", Name: "db.invalid", IsNotFound: true}
+	dnsTimeout := &net.DNSError{Err: "i/o timeout", Name: "db.example", IsTimeout: true}
+
+	tests := []struct {
+		name                        string
+		err                         error
+		network, timeout, temporary bool
+	}{
+		{name: "nil", err: nil},
+		{name: "context canceled", err: context.Canceled},
+		{name: "context deadline", err: context.DeadlineExceeded},
+		{name: "wrapped context deadline", err: fmt.Errorf("query users: %w", context.DeadlineExceeded)},
+		{name: "connection refused", err: dialErr, network: true},
+		{name: "wrapped ECONNRESET", err: fmt.Errorf("read: %w", syscall.ECONNRESET), network: true},
+		{name: "unexpected EOF", err: fmt.Errorf("read response: %w", io.ErrUnexpectedEOF), network: true},
+		{name: "driver EOF text", err: fmt.Errorf("pq: read tcp 10.0.0.1:5432: EOF"), network: true},
+		{name: "bare io.EOF", err: io.EOF},
+		{name: "eof inside a word", err: fmt.Errorf("invalid geofence")},
+		{name: "DNS not found", err: dnsNotFound},
+		{name: "DNS timeout", err: dnsTimeout, network: true, timeout: true, temporary: true},
+		{name: "I/O deadline", err: fmt.Errorf("read: %w", os.ErrDeadlineExceeded), network: true, timeout: true, temporary: true},
+		{name: "timeout text", err: fmt.Errorf("pq: canceling statement due to statement timeout"), timeout: true},
+		{name: "temporary", err: tempErr{}, temporary: true},
+		{name: "plain error", err: fmt.Errorf("duplicate key value")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsNetworkError(tt.err); got != tt.network {
+				t.Errorf("IsNetworkError(%v) = %v, want %v", tt.err, got, tt.network)
+			}
+			if got := IsTimeout(tt.err); got != tt.timeout {
+				t.Errorf("IsTimeout(%v) = %v, want %v", tt.err, got, tt.timeout)
+			}
+			if got := IsTemporary(tt.err); got != tt.temporary {
+				t.Errorf("IsTemporary(%v) = %v, want %v", tt.err, got, tt.temporary)
+			}
+		})
+	}
+}
+
+func TestRetryIf(t *testing.T) {
+	shouldRetry := RetryIf(IsNetworkError, IsTimeout)
+	if shouldRetry(nil) {
+		t.Error("nil must not be retried")
+	}
+	if !shouldRetry(fmt.Errorf("read: %w", syscall.ECONNRESET)) {
+		t.Error("connection reset must be retried")
+	}
+	if shouldRetry(fmt.Errorf("query: %w", context.DeadlineExceeded)) {
+		t.Error("expired context must not be retried")
+	}
+}
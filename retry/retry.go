@@ -32,6 +32,8 @@ type Config struct {
 	LogLevelWhenFailure          log.Level
 	ShouldLogNumRetriesOnSuccess bool
 	LogLevelWhenSuccess          log.Level
+	// Optional; when set, calls fail fast while the operation's circuit is open.
+	Breaker *Breaker
 }
 
 const (
@@ -55,23 +57,38 @@ func DefaultConfig() *Config {
 
 /*
 Pass nil for shouldRetryFn in order to always retry.
+A failure can be told apart with errors.Is: ErrUnretryable when shouldRetryFn rejected the
+error, ErrRetriesExhausted when MaxNumRetries was reached.
 */
 func Retry(ctx context.Context, cfg *Config, retryableOperationFn func(ctx context.Context) ([]interface{}, error),
 	shouldRetryFn func(error) bool, descriptionOfOperation string) ([]interface{}, error) {
 	delayBeforeRetryMS := cfg.InitialDelayBeforeRetrying.Milliseconds()
 	var numRetries int32
 performOperation:
+	if cfg.Breaker != nil {
+		if err := cfg.Breaker.Allow(descriptionOfOperation); err != nil {
+			return nil, errors.Wrapf(err, "Failed fast after %d retries: %s", numRetries, descriptionOfOperation)
+		}
+	}
 	result, err := retryableOperationFn(ctx)
-	if err != nil {
-		if cfg.MaxNumRetries != InfiniteRetries && numRetries == cfg.MaxNumRetries {
-			return nil, errors.Wrapf(err, "Failed after max %d retries: %s", numRetries, descriptionOfOperation)
+	if cfg.Breaker != nil {
+		// Unretryable errors mean the dependency answered, so they don't count against the circuit.
+		if err != nil && (shouldRetryFn == nil || shouldRetryFn(err)) {
+			cfg.Breaker.RecordFailure(descriptionOfOperation)
+		} else {
+			cfg.Breaker.RecordSuccess(descriptionOfOperation)
 		}
-
+	}
+	if err != nil {
 		if shouldRetryFn != nil && !shouldRetryFn(err) {
-			return nil, errors.Wrapf(err, "Failed, unretryable, after %d retries: %s", numRetries,
+			return nil, errors.Wrapf(mark(ErrUnretryable, err), "Failed, unretryable, after %d retries: %s", numRetries,
 				descriptionOfOperation)
 		}
 
+		if cfg.MaxNumRetries != InfiniteRetries && numRetries == cfg.MaxNumRetries {
+			return nil, errors.Wrapf(mark(ErrRetriesExhausted, err), "Failed after max %d retries: %s", numRetries, descriptionOfOperation)
+		}
+
 		numRetries++
 
 		if numRetries > 1 {
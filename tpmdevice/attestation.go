@@ -0,0 +1,233 @@
+package tpmdevice
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/google/go-tpm/legacy/tpm2"
+	"github.com/google/go-tpm/tpmutil"
+)
+
+var (
+	ErrNoCreationAttestation = errors.New("tpmdevice: no creation attestation for this key")
+
+	// ErrInvalidCreationAttestation is returned by VerifyCreationAttestation.
+	ErrInvalidCreationAttestation = errors.New("tpmdevice: invalid creation attestation")
+)
+
+// creationAttestationV2 is the TPM2_CertifyCreation output for the signing key,
+// stored next to (and keyed by) the persistent handle so it survives restarts. Name ties
+// it to the key itself, so a file left behind by a replaced key is never served.
+//
+// The signer is an attestation key (AK), not the signing key: a key can't meaningfully
+// vouch for itself, since the signing key signs whatever Sign is given, a forged
+// TPMS_ATTEST included. The AK is restricted, so the TPM only lets it sign structures the
+// TPM produced itself. Version 1 files were self-certified and are no longer served.
+type creationAttestationV2 struct {
+	V         int    `json:"v"`
+	Handle    uint32 `json:"handle"`
+	Name      []byte `json:"name"`       // TPM name of the certified key (nameAlg || H(public area))
+	KeyPublic []byte `json:"key_public"` // TPMT_PUBLIC of the certified key
+	AKPublic  []byte `json:"ak_public"`  // TPMT_PUBLIC of the attestation key
+	Attest    []byte `json:"attest"`     // TPMS_ATTEST
+	Signature []byte `json:"signature"`  // TPMT_SIGNATURE by the AK over Attest
+}
+
+// akTemplate is the usual ECC attestation key: a restricted ECDSA P-256 signing key. As a
+// primary in the endorsement hierarchy it is derived from the endorsement seed, so the
+// same TPM recreates the same AK every time and it needs no persistent handle.
+var akTemplate = tpm2.Public{
+	Type:       tpm2.AlgECC,
+	NameAlg:    tpm2.AlgSHA256,
+	Attributes: tpm2.FlagSignerDefault | tpm2.FlagNoDA,
+	ECCParameters: &tpm2.ECCParams{
+		Sign: &tpm2.SigScheme{
+			Alg:  tpm2.AlgECDSA,
+			Hash: tpm2.AlgSHA256,
+		},
+		CurveID: tpm2.CurveNISTP256,
+	},
+}
+
+// certifyCreation has the attestation key certify a freshly created primary key's
+// creation data. Must run while the transient handle h is still loaded. The key's own
+// auth isn't needed: TPM2_CertifyCreation only authorizes the signer.
+func certifyCreation(rwc io.ReadWriter, endorsementAuth string, h tpmutil.Handle, pub tpm2.Public, name []byte, creationHash []byte, ticket tpm2.Ticket) (*creationAttestationV2, error) {
+	keyPublic, err := pub.Encode()
+	if err != nil {
+		return nil, fmt.Errorf("tpmdevice: encode key public area: %w", err)
+	}
+
+	ak, _, err := tpm2.CreatePrimary(rwc, tpm2.HandleEndorsement, tpm2.PCRSelection{}, endorsementAuth, "", akTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("tpmdevice: create attestation key: %w", err)
+	}
+	defer func() { _ = tpm2.FlushContext(rwc, ak) }()
+
+	akPub, _, _, err := tpm2.ReadPublic(rwc, ak)
+	if err != nil {
+		return nil, fmt.Errorf("tpmdevice: ReadPublic attestation key: %w", err)
+	}
+	akPublic, err := akPub.Encode()
+	if err != nil {
+		return nil, fmt.Errorf("tpmdevice: encode attestation key public area: %w", err)
+	}
+
+	attest, sig, err := tpm2.CertifyCreation(
+		rwc,
+		"",
+		h,
+		ak,
+		nil,
+		creationHash,
+		*akTemplate.ECCParameters.Sign,
+		ticket,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("tpmdevice: CertifyCreation: %w", err)
+	}
+	return &creationAttestationV2{
+		V:         2,
+		Name:      name,
+		KeyPublic: keyPublic,
+		AKPublic:  akPublic,
+		Attest:    attest,
+		Signature: sig,
+	}, nil
+}
+
+// VerifyCreationAttestation checks a document returned by CreationAttestation against
+// pub, the signing key's uncompressed public key: the TPMS_ATTEST must be a creation
+// attestation for a TPM-resident (fixedTPM, TPM-generated) key with that public key,
+// signed by a restricted TPM signing key. It returns that attestation key's TPMT_PUBLIC.
+//
+// The document alone only shows that the key lives in the same TPM as the AK. The caller
+// must still trust the AK, e.g. by binding it to the TPM's endorsement key through
+// TPM2_ActivateCredential, or by comparing it with an AK recorded at enrollment.
+func VerifyCreationAttestation(doc []byte, pub []byte) ([]byte, error) {
+	var att creationAttestationV2
+	if err := json.Unmarshal(doc, &att); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCreationAttestation, err)
+	}
+	if att.V != 2 {
+		return nil, fmt.Errorf("%w: unsupported version %d", ErrInvalidCreationAttestation, att.V)
+	}
+
+	akPub, err := tpm2.DecodePublic(att.AKPublic)
+	if err != nil {
+		return nil, fmt.Errorf("%w: attestation key: %v", ErrInvalidCreationAttestation, err)
+	}
+	const akAttrs = tpm2.FlagSign | tpm2.FlagRestricted | tpm2.FlagFixedTPM
+	if akPub.Attributes&akAttrs != akAttrs {
+		return nil, fmt.Errorf("%w: attestation key is not a restricted TPM signing key", ErrInvalidCreationAttestation)
+	}
+	akKey, err := akPub.Key()
+	if err != nil {
+		return nil, fmt.Errorf("%w: attestation key: %v", ErrInvalidCreationAttestation, err)
+	}
+	akECDSA, ok := akKey.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("%w: attestation key is not ECDSA", ErrInvalidCreationAttestation)
+	}
+
+	sig, err := tpm2.DecodeSignature(bytes.NewBuffer(att.Signature))
+	if err != nil || sig.ECC == nil || sig.ECC.HashAlg != tpm2.AlgSHA256 {
+		return nil, fmt.Errorf("%w: signature is not ECDSA-SHA256", ErrInvalidCreationAttestation)
+	}
+	digest := sha256.Sum256(att.Attest)
+	if !ecdsa.Verify(akECDSA, digest[:], sig.ECC.R, sig.ECC.S) {
+		return nil, fmt.Errorf("%w: bad signature", ErrInvalidCreationAttestation)
+	}
+
+	ad, err := tpm2.DecodeAttestationData(att.Attest)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCreationAttestation, err)
+	}
+	if ad.Type != tpm2.TagAttestCreation || ad.AttestedCreationInfo == nil {
+		return nil, fmt.Errorf("%w: not a creation attestation", ErrInvalidCreationAttestation)
+	}
+
+	keyPub, err := tpm2.DecodePublic(att.KeyPublic)
+	if err != nil {
+		return nil, fmt.Errorf("%w: key: %v", ErrInvalidCreationAttestation, err)
+	}
+	if match, err := ad.AttestedCreationInfo.Name.MatchesPublic(keyPub); err != nil || !match {
+		return nil, fmt.Errorf("%w: attested name is not the key's", ErrInvalidCreationAttestation)
+	}
+	const keyAttrs = tpm2.FlagFixedTPM | tpm2.FlagFixedParent | tpm2.FlagSensitiveDataOrigin
+	if keyPub.Attributes&keyAttrs != keyAttrs {
+		return nil, fmt.Errorf("%w: key was not generated in and bound to the TPM", ErrInvalidCreationAttestation)
+	}
+	keyPoint, err := publicToUncompressed(keyPub)
+	if err != nil || !bytes.Equal(keyPoint, pub) {
+		return nil, fmt.Errorf("%w: attested key is not pub", ErrInvalidCreationAttestation)
+	}
+	return att.AKPublic, nil
+}
+
+func attestationPath(cfg Config, h tpmutil.Handle) string {
+	dir := cfg.AttestationDir
+	if dir == "" {
+		base, err := os.UserConfigDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(base, "quantumauth", "tpm")
+	}
+	return filepath.Join(dir, fmt.Sprintf("attest-0x%x.json", uint32(h)))
+}
+
+func writeCreationAttestation(path string, att *creationAttestationV2) error {
+	if path == "" {
+		return errors.New("tpmdevice: no attestation path")
+	}
+	out, err := json.MarshalIndent(att, "", "  ")
+	if err != nil {
+		return fmt.Errorf("tpmdevice: marshal attestation: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("tpmdevice: mkdir attestation dir: %w", err)
+	}
+	return os.WriteFile(path, out, 0o600)
+}
+
+// CreationAttestation returns the stored JSON {v, handle, name, key_public, ak_public,
+// attest, signature} produced by TPM2_CertifyCreation when this key was created; check it
+// with VerifyCreationAttestation. It returns ErrNoCreationAttestation when the stored
+// name isn't the current key's, and for version 1 (self-certified) files.
+func (c *client) CreationAttestation() ([]byte, error) {
+	if c == nil || c.attestPath == "" {
+		return nil, ErrNoCreationAttestation
+	}
+	b, err := os.ReadFile(c.attestPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrNoCreationAttestation
+		}
+		return nil, fmt.Errorf("tpmdevice: read attestation: %w", err)
+	}
+
+	var att creationAttestationV2
+	if err := json.Unmarshal(b, &att); err != nil {
+		return nil, fmt.Errorf("tpmdevice: unmarshal attestation: %w", err)
+	}
+	if att.V != 2 || att.Handle != uint32(c.handle) || len(att.Name) == 0 {
+		return nil, ErrNoCreationAttestation
+	}
+
+	_, name, _, err := tpm2.ReadPublic(c.rwc, c.handle)
+	if err != nil {
+		return nil, fmt.Errorf("tpmdevice: ReadPublic: %w", err)
+	}
+	if !bytes.Equal(att.Name, name) {
+		return nil, ErrNoCreationAttestation
+	}
+	return b, nil
+}
@@ -0,0 +1,165 @@
+package tpmdevice
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/google/go-tpm/legacy/tpm2"
+)
+
+// eccPublic returns a TPMT_PUBLIC for the P-256 key k with the given attributes.
+func eccPublic(k *ecdsa.PrivateKey, attrs tpm2.KeyProp) tpm2.Public {
+	return tpm2.Public{
+		Type:       tpm2.AlgECC,
+		NameAlg:    tpm2.AlgSHA256,
+		Attributes: attrs,
+		ECCParameters: &tpm2.ECCParams{
+			Sign:    &tpm2.SigScheme{Alg: tpm2.AlgECDSA, Hash: tpm2.AlgSHA256},
+			CurveID: tpm2.CurveNISTP256,
+			Point: tpm2.ECPoint{
+				XRaw: k.PublicKey.X.FillBytes(make([]byte, 32)),
+				YRaw: k.PublicKey.Y.FillBytes(make([]byte, 32)),
+			},
+		},
+	}
+}
+
+// fakeCreationAttestation builds the document CreationAttestation would return for key,
+// with the TPM's part (the TPMS_ATTEST and the AK's signature over it) done in software.
+func fakeCreationAttestation(t *testing.T, key, ak *ecdsa.PrivateKey, akAttrs tpm2.KeyProp) []byte {
+	t.Helper()
+	keyPub := eccPublic(key, tpm2.FlagSignerDefault&^tpm2.FlagRestricted)
+	akPub := eccPublic(ak, akAttrs)
+
+	keyName, err := keyPub.Name()
+	if err != nil {
+		t.Fatal(err)
+	}
+	akName, err := akPub.Name()
+	if err != nil {
+		t.Fatal(err)
+	}
+	attest, err := tpm2.AttestationData{
+		Magic:                0xff544347, // TPM_GENERATED_VALUE
+		Type:                 tpm2.TagAttestCreation,
+		QualifiedSigner:      akName,
+		AttestedCreationInfo: &tpm2.CreationInfo{Name: keyName, OpaqueDigest: make([]byte, 32)},
+	}.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest := sha256.Sum256(attest)
+	r, s, err := ecdsa.Sign(rand.Reader, ak, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := tpm2.Signature{
+		Alg: tpm2.AlgECDSA,
+		ECC: &tpm2.SignatureECC{HashAlg: tpm2.AlgSHA256, R: r, S: s},
+	}.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyPublic, err := keyPub.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	akPublic, err := akPub.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	name, err := keyName.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := json.Marshal(creationAttestationV2{
+		V:         2,
+		Handle:    0x81000001,
+		Name:      name,
+		KeyPublic: keyPublic,
+		AKPublic:  akPublic,
+		Attest:    attest,
+		Signature: sig,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
+
+func mustKey(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+	k, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return k
+}
+
+func TestVerifyCreationAttestation(t *testing.T) {
+	key, ak := mustKey(t), mustKey(t)
+	pub := uncompressedFromECDSA(&key.PublicKey)
+
+	doc := fakeCreationAttestation(t, key, ak, akTemplate.Attributes)
+	akPublic, err := VerifyCreationAttestation(doc, pub)
+	if err != nil {
+		t.Fatalf("VerifyCreationAttestation: %v", err)
+	}
+	var att creationAttestationV2
+	if err := json.Unmarshal(doc, &att); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(akPublic, att.AKPublic) {
+		t.Fatal("returned AK public area is not the document's")
+	}
+}
+
+func TestVerifyCreationAttestationRejects(t *testing.T) {
+	key, ak := mustKey(t), mustKey(t)
+	pub := uncompressedFromECDSA(&key.PublicKey)
+	good := fakeCreationAttestation(t, key, ak, akTemplate.Attributes)
+
+	tamper := func(f func(att *creationAttestationV2)) []byte {
+		var att creationAttestationV2
+		if err := json.Unmarshal(good, &att); err != nil {
+			t.Fatal(err)
+		}
+		f(&att)
+		b, err := json.Marshal(att)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return b
+	}
+	other := mustKey(t)
+	otherPublic, err := eccPublic(other, tpm2.FlagSignerDefault&^tpm2.FlagRestricted).Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name string
+		doc  []byte
+		pub  []byte
+	}{
+		{"other key", good, uncompressedFromECDSA(&other.PublicKey)},
+		{"self-certified v1", tamper(func(att *creationAttestationV2) { att.V = 1 }), pub},
+		{"tampered attest", tamper(func(att *creationAttestationV2) { att.Attest[len(att.Attest)-1] ^= 1 }), pub},
+		{"swapped key public", tamper(func(att *creationAttestationV2) { att.KeyPublic = otherPublic }), uncompressedFromECDSA(&other.PublicKey)},
+		{"unrestricted signer", fakeCreationAttestation(t, key, ak, akTemplate.Attributes&^tpm2.FlagRestricted), pub},
+		{"signed by the key itself", fakeCreationAttestation(t, key, key, tpm2.FlagSignerDefault&^tpm2.FlagRestricted), pub},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := VerifyCreationAttestation(tc.doc, tc.pub); !errors.Is(err, ErrInvalidCreationAttestation) {
+				t.Fatalf("err = %v, want ErrInvalidCreationAttestation", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package tpmdevice
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/google/go-tpm/legacy/tpm2"
+	"github.com/google/go-tpm/tpmutil"
+)
+
+// TPMClock is TPMS_TIME_INFO from TPM2_ReadClock.
+//
+// Clock only moves forward across power cycles (it is persisted in NV), so a server
+// that stores the last value it saw can spot rollback; ResetCount increments on every
+// TPM reset (reboot) and RestartCount on every resume from hibernate.
+type TPMClock struct {
+	Time         uint64 // ms since the last TPM reset
+	Clock        uint64 // ms the TPM has been powered, persisted across resets
+	ResetCount   uint32
+	RestartCount uint32
+	Safe         bool // false if Clock may have been lost and reset to a lower value
+}
+
+// OpenTPM opens the platform TPM for callers that need raw access (ReadClock, IncrementCounter).
+func OpenTPM() (io.ReadWriteCloser, error) {
+	return openTPM()
+}
+
+// ReadClock returns the TPM's clock and reset/restart counters.
+func ReadClock(rw io.ReadWriter) (*TPMClock, error) {
+	resp, code, err := tpmutil.RunCommand(rw, tpm2.TagNoSessions, tpm2.CmdReadClock)
+	if err != nil {
+		return nil, fmt.Errorf("tpmdevice: ReadClock: %w", err)
+	}
+	if code != tpmutil.RCSuccess {
+		return nil, fmt.Errorf("tpmdevice: ReadClock: response code 0x%x", code)
+	}
+
+	var (
+		c    TPMClock
+		safe byte
+	)
+	if _, err := tpmutil.Unpack(resp, &c.Time, &c.Clock, &c.ResetCount, &c.RestartCount, &safe); err != nil {
+		return nil, fmt.Errorf("tpmdevice: ReadClock: decode: %w", err)
+	}
+	c.Safe = safe != 0
+	return &c, nil
+}
+
+// nvTypeCounter is TPM_NT_COUNTER in the TPMA_NV type bits.
+const nvTypeCounter tpm2.NVAttr = 0x1 << 4
+
+// IncrementCounter bumps the monotonic NV counter at index and returns its new value,
+// defining the index on first use. The counter can never go backwards, even across
+// reboots or owner clears that don't also clear NV, so it makes a rollback detector.
+//
+// The index uses empty auth: anyone can increment it, which is harmless for a counter
+// that only moves forward.
+func IncrementCounter(rw io.ReadWriter, index tpmutil.Handle, ownerAuth string) (uint64, error) {
+	if _, err := tpm2.NVReadPublic(rw, index); err != nil {
+		if !isHandleEmptyErr(err) {
+			return 0, fmt.Errorf("tpmdevice: NV read public 0x%x: %w", index, err)
+		}
+		attrs := nvTypeCounter | tpm2.AttrAuthRead | tpm2.AttrAuthWrite | tpm2.AttrNoDA
+		if err := tpm2.NVDefineSpace(rw, tpm2.HandleOwner, index, ownerAuth, "", nil, attrs, 8); err != nil {
+			return 0, fmt.Errorf("tpmdevice: define NV counter 0x%x: %w", index, err)
+		}
+	}
+
+	if err := tpm2.NVIncrement(rw, index, ""); err != nil {
+		return 0, fmt.Errorf("tpmdevice: increment NV counter 0x%x: %w", index, err)
+	}
+
+	raw, err := tpm2.NVReadEx(rw, index, index, "", 0)
+	if err != nil {
+		return 0, fmt.Errorf("tpmdevice: read NV counter 0x%x: %w", index, err)
+	}
+	if len(raw) != 8 {
+		return 0, fmt.Errorf("tpmdevice: NV counter 0x%x: got %d bytes, want 8", index, len(raw))
+	}
+	return binary.BigEndian.Uint64(raw), nil
+}
@@ -46,8 +46,18 @@ func newEnclaveClient(_ context.Context, cfg Config) (Client, error) {
 }
 
 // PublicKey etc would be implemented once the C helpers exist
-func (c *enclaveClient) PublicKey() []byte                  { return append([]byte(nil), c.pub...) }
-func (c *enclaveClient) PublicKeyB64() string               { return c.pubB64 }
-func (c *enclaveClient) Sign(msg []byte) ([]byte, error)    { return nil, fmt.Errorf("not implemented") }
-func (c *enclaveClient) SignB64(msg []byte) (string, error) { return "", fmt.Errorf("not implemented") }
-func (c *enclaveClient) Close() error                       { return nil }
+func (c *enclaveClient) PublicKey() []byte                    { return append([]byte(nil), c.pub...) }
+func (c *enclaveClient) PublicKeyB64() string                 { return c.pubB64 }
+func (c *enclaveClient) Sign(msg []byte) ([]byte, error)      { return nil, fmt.Errorf("not implemented") }
+func (c *enclaveClient) SignB64(msg []byte) (string, error)   { return "", fmt.Errorf("not implemented") }
+func (c *enclaveClient) CreationAttestation() ([]byte, error) { return nil, ErrNoCreationAttestation }
+func (c *enclaveClient) RequiresAuth() bool                   { return false }
+func (c *enclaveClient) Close() error                         { return nil }
+
+func (c *enclaveClient) SignWithAuth(msg []byte, auth string) ([]byte, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (c *enclaveClient) SignWithAuthB64(msg []byte, auth string) (string, error) {
+	return "", fmt.Errorf("not implemented")
+}
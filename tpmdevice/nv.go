@@ -0,0 +1,100 @@
+package tpmdevice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/go-tpm/legacy/tpm2"
+	"github.com/google/go-tpm/tpmutil"
+)
+
+const (
+	// MaxNVDataSize is the largest record NVWrite accepts; TPMs guarantee at least this much per index.
+	MaxNVDataSize = 2048
+
+	nvWriteChunk = 512 // below MAX_NV_BUFFER_SIZE on every TPM we've seen
+
+	// Attributes NVWrite defines indexes with; an index with others isn't ours to touch.
+	nvAttrs = tpm2.AttrAuthWrite | tpm2.AttrOwnerRead | tpm2.AttrNoDA
+)
+
+// ErrNVIndexNotOwned is returned by NVWrite for an existing index that wasn't defined by
+// NVWrite (its attributes differ), so it is neither overwritten nor undefined.
+var ErrNVIndexNotOwned = errors.New("tpmdevice: NV index was not defined by NVWrite")
+
+// NVWrite stores data at the NV index, defining it on first use. The index is sized to
+// exactly len(data). An existing index is only reused if it has NVWrite's attributes:
+// with the same size it is overwritten in place, with another size it is undefined and
+// redefined (using empty owner auth). Any other index returns ErrNVIndexNotOwned.
+//
+// auth protects writes; reads go through owner auth so NVRead needs no secret.
+func NVWrite(ctx context.Context, index tpmutil.Handle, data []byte, auth string) error {
+	_ = ctx // TPM commands aren't cancellable; keep ctx for symmetry with the rest of the API
+	if len(data) == 0 {
+		return fmt.Errorf("tpmdevice: NVWrite: empty data")
+	}
+	if len(data) > MaxNVDataSize {
+		return fmt.Errorf("tpmdevice: NVWrite: %d bytes exceeds max %d", len(data), MaxNVDataSize)
+	}
+
+	rwc, err := openTPM()
+	if err != nil {
+		return err
+	}
+	defer rwc.Close()
+
+	pub, err := tpm2.NVReadPublic(rwc, index)
+	switch {
+	case err == nil && pub.Attributes&^tpm2.AttrWritten != nvAttrs:
+		return fmt.Errorf("%w: 0x%x has attributes 0x%x", ErrNVIndexNotOwned, index, uint32(pub.Attributes))
+	case err == nil && int(pub.DataSize) == len(data):
+		// reuse as is
+	case err == nil:
+		if err := tpm2.NVUndefineSpace(rwc, "", tpm2.HandleOwner, index); err != nil {
+			return fmt.Errorf("tpmdevice: undefine NV 0x%x (size %d, want %d): %w", index, pub.DataSize, len(data), err)
+		}
+		fallthrough
+	case isHandleEmptyErr(err):
+		if err := tpm2.NVDefineSpace(rwc, tpm2.HandleOwner, index, "", auth, nil, nvAttrs, uint16(len(data))); err != nil {
+			return fmt.Errorf("tpmdevice: define NV 0x%x: %w", index, err)
+		}
+	default:
+		return fmt.Errorf("tpmdevice: NV read public 0x%x: %w", index, err)
+	}
+
+	for off := 0; off < len(data); off += nvWriteChunk {
+		end := min(off+nvWriteChunk, len(data))
+		if err := tpm2.NVWrite(rwc, index, index, auth, data[off:end], uint16(off)); err != nil {
+			return fmt.Errorf("tpmdevice: write NV 0x%x at %d: %w", index, off, err)
+		}
+	}
+	return nil
+}
+
+// NVRead returns the contents of an NV index written by NVWrite (or any index that is
+// owner-readable with empty owner auth, or readable with an empty index auth).
+func NVRead(ctx context.Context, index tpmutil.Handle) ([]byte, error) {
+	_ = ctx
+
+	rwc, err := openTPM()
+	if err != nil {
+		return nil, err
+	}
+	defer rwc.Close()
+
+	pub, err := tpm2.NVReadPublic(rwc, index)
+	if err != nil {
+		return nil, fmt.Errorf("tpmdevice: NV read public 0x%x: %w", index, err)
+	}
+
+	authHandle := index
+	if pub.Attributes&tpm2.AttrOwnerRead != 0 {
+		authHandle = tpm2.HandleOwner
+	}
+	data, err := tpm2.NVReadEx(rwc, index, authHandle, "", 0)
+	if err != nil {
+		return nil, fmt.Errorf("tpmdevice: read NV 0x%x: %w", index, err)
+	}
+	return data, nil
+}
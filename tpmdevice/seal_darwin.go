@@ -9,7 +9,8 @@ import (
 
 type noSealer struct{}
 
-func NewSealer(_ string) Sealer { return &noSealer{} }
+func NewSealer(_ string) Sealer                            { return &noSealer{} }
+func NewSealerWithParent(_ string, _ SealingParent) Sealer { return &noSealer{} }
 func (s *noSealer) Seal(context.Context, string, []byte) ([]byte, error) {
 	return nil, errors.New("tpm sealer not supported on darwin")
 }
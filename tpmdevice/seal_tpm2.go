@@ -15,17 +15,28 @@ import (
 
 type tpm2Sealer struct {
 	ownerAuth string
+	parent    SealingParent
 }
 
 type sealedBlobV1 struct {
-	V     int    `json:"v"`
-	Label string `json:"label"`
-	Priv  []byte `json:"priv"` // []byte becomes base64 automatically in JSON
-	Pub   []byte `json:"pub"`
+	V      int           `json:"v"`
+	Label  string        `json:"label"`
+	Parent SealingParent `json:"parent,omitempty"` // empty = ECC (blobs written before this field existed)
+	Priv   []byte        `json:"priv"`             // []byte becomes base64 automatically in JSON
+	Pub    []byte        `json:"pub"`
 }
 
 func NewSealer(ownerAuth string) Sealer {
-	return &tpm2Sealer{ownerAuth: ownerAuth}
+	return NewSealerWithParent(ownerAuth, SealingParentECC)
+}
+
+// NewSealerWithParent seals under the given storage parent template.
+// Unseal always recreates the parent recorded in the blob.
+func NewSealerWithParent(ownerAuth string, parent SealingParent) Sealer {
+	if parent == "" {
+		parent = SealingParentECC
+	}
+	return &tpm2Sealer{ownerAuth: ownerAuth, parent: parent}
 }
 
 func (s *tpm2Sealer) Seal(ctx context.Context, label string, secret []byte) ([]byte, error) {
@@ -39,7 +50,7 @@ func (s *tpm2Sealer) Seal(ctx context.Context, label string, secret []byte) ([]b
 	}
 	defer rwc.Close()
 
-	parent, err := createPrimaryStorageKey(rwc, s.ownerAuth)
+	parent, err := createPrimaryStorageKey(rwc, s.ownerAuth, s.parent)
 	if err != nil {
 		return nil, err
 	}
@@ -74,10 +85,11 @@ func (s *tpm2Sealer) Seal(ctx context.Context, label string, secret []byte) ([]b
 	}
 
 	out, err := json.Marshal(sealedBlobV1{
-		V:     1,
-		Label: label,
-		Priv:  privBlob,
-		Pub:   pubBlob,
+		V:      1,
+		Label:  label,
+		Parent: s.parent,
+		Priv:   privBlob,
+		Pub:    pubBlob,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("tpmdevice: marshal sealed blob: %w", err)
@@ -103,7 +115,11 @@ func (s *tpm2Sealer) Unseal(ctx context.Context, label string, blob []byte) ([]b
 		return nil, errors.New("tpmdevice: sealed blob label mismatch")
 	}
 
-	parent, err := createPrimaryStorageKey(rwc, s.ownerAuth)
+	blobParent := sb.Parent
+	if blobParent == "" {
+		blobParent = SealingParentECC
+	}
+	parent, err := createPrimaryStorageKey(rwc, s.ownerAuth, blobParent)
 	if err != nil {
 		return nil, err
 	}
@@ -122,8 +138,39 @@ func (s *tpm2Sealer) Unseal(ctx context.Context, label string, blob []byte) ([]b
 	return secret, nil
 }
 
-func createPrimaryStorageKey(rwc io.ReadWriter, ownerAuth string) (tpmutil.Handle, error) {
-	template := tpm2.Public{
+func createPrimaryStorageKey(rwc io.ReadWriter, ownerAuth string, parent SealingParent) (tpmutil.Handle, error) {
+	template, err := storageKeyTemplate(parent)
+	if err != nil {
+		return 0, err
+	}
+
+	h, _, err := tpm2.CreatePrimary(
+		rwc,
+		tpm2.HandleOwner,
+		tpm2.PCRSelection{},
+		"",        // parentPassword
+		ownerAuth, // ownerPassword
+		template,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("tpmdevice: CreatePrimary(storage %s): %w", parent, err)
+	}
+	return h, nil
+}
+
+func storageKeyTemplate(parent SealingParent) (tpm2.Public, error) {
+	switch parent {
+	case SealingParentECC:
+		return eccStorageKeyTemplate(), nil
+	case SealingParentRSA2048:
+		return rsaStorageKeyTemplate(), nil
+	default:
+		return tpm2.Public{}, fmt.Errorf("tpmdevice: unknown sealing parent %q", parent)
+	}
+}
+
+func eccStorageKeyTemplate() tpm2.Public {
+	return tpm2.Public{
 		Type:    tpm2.AlgECC,
 		NameAlg: tpm2.AlgSHA256,
 		Attributes: tpm2.FlagDecrypt |
@@ -143,17 +190,28 @@ func createPrimaryStorageKey(rwc io.ReadWriter, ownerAuth string) (tpmutil.Handl
 			},
 		},
 	}
+}
 
-	h, _, err := tpm2.CreatePrimary(
-		rwc,
-		tpm2.HandleOwner,
-		tpm2.PCRSelection{},
-		"",        // parentPassword
-		ownerAuth, // ownerPassword
-		template,
-	)
-	if err != nil {
-		return 0, fmt.Errorf("tpmdevice: CreatePrimary(storage): %w", err)
+// rsaStorageKeyTemplate is the TCG default SRK template (RSA-2048, AES-128-CFB).
+func rsaStorageKeyTemplate() tpm2.Public {
+	return tpm2.Public{
+		Type:    tpm2.AlgRSA,
+		NameAlg: tpm2.AlgSHA256,
+		Attributes: tpm2.FlagDecrypt |
+			tpm2.FlagRestricted |
+			tpm2.FlagFixedTPM |
+			tpm2.FlagFixedParent |
+			tpm2.FlagSensitiveDataOrigin |
+			tpm2.FlagUserWithAuth |
+			tpm2.FlagNoDA,
+		RSAParameters: &tpm2.RSAParams{
+			Symmetric: &tpm2.SymScheme{
+				Alg:     tpm2.AlgAES,
+				KeyBits: 128,
+				Mode:    tpm2.AlgCFB,
+			},
+			KeyBits:    2048,
+			ModulusRaw: make([]byte, 256),
+		},
 	}
-	return h, nil
 }
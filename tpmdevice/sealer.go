@@ -8,3 +8,13 @@ type Sealer interface {
 	Seal(ctx context.Context, label string, secret []byte) ([]byte, error)
 	Unseal(ctx context.Context, label string, blob []byte) ([]byte, error)
 }
+
+// SealingParent selects the primary storage key template sealed objects are created under.
+type SealingParent string
+
+const (
+	// SealingParentECC is an ECC P-256 storage key (default).
+	SealingParentECC SealingParent = "ecc-p256"
+	// SealingParentRSA2048 is the TCG default RSA-2048 SRK template, for tooling that assumes it.
+	SealingParentRSA2048 SealingParent = "rsa-2048"
+)
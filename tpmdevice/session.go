@@ -0,0 +1,81 @@
+package tpmdevice
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/google/go-tpm/legacy/tpm2"
+	"github.com/google/go-tpm/tpmutil"
+)
+
+func startPolicySession(rwc io.ReadWriter) (tpmutil.Handle, error) {
+	sess, _, err := tpm2.StartAuthSession(
+		rwc,
+		tpm2.HandleNull,
+		tpm2.HandleNull,
+		make([]byte, 16),
+		nil,
+		tpm2.SessionPolicy,
+		tpm2.AlgNull,
+		tpm2.AlgSHA256,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("tpmdevice: start policy session: %w", err)
+	}
+	return sess, nil
+}
+
+// Session reuse (Config.ReuseSession) only applies to keys created with Config.KeyAuth:
+// they sign through a PolicyPassword session, which otherwise costs a StartAuthSession
+// and a FlushContext per signature. Sign on keys without KeyAuth uses a plain password
+// authorization, has no session to reuse and is unaffected.
+
+// signWithReusedSession signs in c's long-lived policy session. A successful Sign leaves
+// the session open (continueSession) with its policy reset, so each call re-runs
+// PolicyPassword. After any failure the policy state is unknown (a failed Sign doesn't
+// reset it, and a second PolicyPassword would then fail), so the session is flushed and
+// the next call starts a new one. If the TPM had lost the session the sign is retried
+// once; auth failures are never retried, so a wrong PIN costs exactly one
+// dictionary-attack strike.
+func (c *client) signWithReusedSession(digest []byte, auth string) ([]byte, error) {
+	c.sessMu.Lock()
+	defer c.sessMu.Unlock()
+
+	for attempt := 0; ; attempt++ {
+		if c.sess == 0 {
+			sess, err := startPolicySession(c.rwc)
+			if err != nil {
+				return nil, err
+			}
+			c.sess = sess
+		}
+
+		raw, err := c.signInPolicySession(c.sess, digest, auth)
+		if err == nil {
+			return raw, nil
+		}
+		_ = tpm2.FlushContext(c.rwc, c.sess)
+		c.sess = 0
+		if attempt > 0 || !isStaleSessionErr(err) {
+			return nil, err
+		}
+	}
+}
+
+// isStaleSessionErr reports TPM errors meaning the session handle is no longer usable.
+func isStaleSessionErr(err error) bool {
+	var warn tpm2.Warning
+	if errors.As(err, &warn) {
+		return warn.Code == tpm2.RCReferenceS0 || warn.Code == tpm2.RCReferenceH0
+	}
+	var sessErr tpm2.SessionError
+	if errors.As(err, &sessErr) {
+		return sessErr.Code == tpm2.RCHandle || sessErr.Code == tpm2.RCExpired
+	}
+	var handleErr tpm2.HandleError
+	if errors.As(err, &handleErr) {
+		return handleErr.Code == tpm2.RCHandle
+	}
+	return false
+}
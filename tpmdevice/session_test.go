@@ -0,0 +1,61 @@
+package tpmdevice
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/google/go-tpm/tpmutil"
+)
+
+// BenchmarkSignWithAuth compares a policy session per signature with a reused one. It
+// needs a real TPM and a persistent handle it may overwrite and evict, e.g.
+//
+//	QA_TPM_BENCH_HANDLE=0x81010099 go test -run '^$' -bench SignWithAuth ./tpmdevice
+func BenchmarkSignWithAuth(b *testing.B) {
+	raw := os.Getenv("QA_TPM_BENCH_HANDLE")
+	if raw == "" {
+		b.Skip("set QA_TPM_BENCH_HANDLE to a disposable persistent handle to run against a real TPM")
+	}
+	h, err := strconv.ParseUint(raw, 0, 32)
+	if err != nil {
+		b.Fatalf("QA_TPM_BENCH_HANDLE: %v", err)
+	}
+
+	const pin = "bench-pin"
+	msg := []byte("quantumauth tpmdevice benchmark")
+
+	for _, bc := range []struct {
+		name  string
+		reuse bool
+	}{
+		{"SessionPerSign", false},
+		{"ReusedSession", true},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			cfg := Config{
+				Handle:         tpmutil.Handle(h),
+				ForceNew:       true,
+				AttestationDir: b.TempDir(),
+				KeyAuth:        pin,
+				ReuseSession:   bc.reuse,
+			}
+			c, err := NewWithConfig(context.Background(), cfg)
+			if err != nil {
+				b.Fatalf("NewWithConfig: %v", err)
+			}
+			b.Cleanup(func() {
+				_ = c.Close()
+				_ = Deprovision(context.Background(), cfg)
+			})
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := c.SignWithAuth(msg, pin); err != nil {
+					b.Fatalf("SignWithAuth: %v", err)
+				}
+			}
+		})
+	}
+}
@@ -5,12 +5,14 @@ import (
 	"crypto/ecdsa"
 	"crypto/sha256"
 	"encoding/base64"
+	"encoding/binary"
 	"errors"
 	"fmt"
 	"io"
 	"os"
 	"runtime"
 	"strings"
+	"sync"
 
 	"github.com/google/go-tpm/legacy/tpm2"
 	"github.com/google/go-tpm/tpmutil"
@@ -30,14 +32,25 @@ type Client interface {
 	PublicKeyB64() string               // base64url(0x04||X||Y)
 	Sign(msg []byte) ([]byte, error)    // raw R||S (64 bytes)
 	SignB64(msg []byte) (string, error) // base64url(R||S)
+	RequiresAuth() bool                 // key was created with Config.KeyAuth
+	SignWithAuth(msg []byte, auth string) ([]byte, error)
+	SignWithAuthB64(msg []byte, auth string) (string, error)
+	CreationAttestation() ([]byte, error)
 	Close() error
 }
 
 type client struct {
-	rwc    io.ReadWriteCloser
-	handle tpmutil.Handle
-	pub    []byte
-	pubB64 string
+	rwc        io.ReadWriteCloser
+	handle     tpmutil.Handle
+	pub        []byte
+	pubB64     string
+	attestPath string
+
+	requiresAuth bool // gated by PolicyPassword; Sign needs SignWithAuth
+
+	reuseSession bool
+	sessMu       sync.Mutex
+	sess         tpmutil.Handle // open policy session when reuseSession; 0 = none
 }
 
 type Config struct {
@@ -47,8 +60,37 @@ type Config struct {
 
 	HandleStart tpmutil.Handle
 	HandleCount uint32
+
+	// Where creation attestations are stored (default: <UserConfigDir>/quantumauth/tpm)
+	AttestationDir string
+
+	// KeyAuth, when set, is the PIN/password gating a newly created signing key: it can
+	// then only sign through SignWithAuth, and wrong guesses count toward the TPM's
+	// dictionary-attack lockout. It has no effect on an existing key, which keeps the
+	// auth it was created with (use ForceNew to replace it).
+	KeyAuth string
+
+	// EndorsementAuth is the endorsement hierarchy's auth, needed to create the
+	// attestation key that certifies a new signing key (see CreationAttestation).
+	// Usually empty.
+	EndorsementAuth string
+
+	// ReuseSession keeps one policy session open across SignWithAuth calls instead of
+	// starting and flushing one per signature, saving two TPM commands per sign. A
+	// session the TPM no longer knows (e.g. after a TPM reset), or one left in an unknown
+	// state by a failed sign, is replaced transparently. It only applies to keys created
+	// with KeyAuth; Sign on other keys uses no session and gains nothing.
+	ReuseSession bool
 }
 
+// ErrKeyAuthRequired is returned by Sign for a key created with Config.KeyAuth.
+var ErrKeyAuthRequired = errors.New("tpmdevice: key requires auth; use SignWithAuth")
+
+// ErrNoStagingHandle is returned by ForceNew when the handle range has no free handle to
+// hold the new key while the old one is evicted. Free a handle (see Deprovision) or widen
+// HandleStart/HandleCount rather than risk losing the only key mid-replacement.
+var ErrNoStagingHandle = errors.New("tpmdevice: no free staging handle")
+
 func (c *client) Handle() tpmutil.Handle {
 	if c == nil {
 		return 0
@@ -110,10 +152,13 @@ func pickOrCreateHandle(rwc io.ReadWriteCloser, cfg Config, start tpmutil.Handle
 			uncompressed, err2 := publicToUncompressed(pub)
 			if err2 == nil {
 				return &client{
-					rwc:    rwc,
-					handle: h,
-					pub:    uncompressed,
-					pubB64: base64.RawStdEncoding.EncodeToString(uncompressed),
+					rwc:          rwc,
+					handle:       h,
+					pub:          uncompressed,
+					pubB64:       base64.RawStdEncoding.EncodeToString(uncompressed),
+					attestPath:   attestationPath(cfg, h),
+					requiresAuth: keyRequiresAuth(pub),
+					reuseSession: cfg.ReuseSession,
 				}, nil
 			}
 
@@ -123,8 +168,7 @@ func pickOrCreateHandle(rwc io.ReadWriteCloser, cfg Config, start tpmutil.Handle
 			)
 
 			if cfg.ForceNew {
-				_ = tpm2.EvictControl(rwc, cfg.OwnerAuth, tpm2.HandleOwner, h, h)
-				return createAndPersistAt(rwc, cfg, h)
+				return replaceAt(rwc, cfg, h)
 			}
 			continue
 		}
@@ -159,8 +203,7 @@ func pickOrCreateHandle(rwc io.ReadWriteCloser, cfg Config, start tpmutil.Handle
 // - if empty -> create & persist
 func openOrCreateAtHandle(rwc io.ReadWriteCloser, cfg Config, h tpmutil.Handle) (Client, error) {
 	if cfg.ForceNew {
-		_ = tpm2.EvictControl(rwc, cfg.OwnerAuth, tpm2.HandleOwner, h, h)
-		return createAndPersistAt(rwc, cfg, h)
+		return replaceAt(rwc, cfg, h)
 	}
 
 	pub, _, _, err := tpm2.ReadPublic(rwc, h)
@@ -171,10 +214,13 @@ func openOrCreateAtHandle(rwc io.ReadWriteCloser, cfg Config, h tpmutil.Handle)
 		}
 		log.Info("tpmdevice using existing key", "handle", fmt.Sprintf("0x%x", h))
 		return &client{
-			rwc:    rwc,
-			handle: h,
-			pub:    uncompressed,
-			pubB64: base64.RawStdEncoding.EncodeToString(uncompressed),
+			rwc:          rwc,
+			handle:       h,
+			pub:          uncompressed,
+			pubB64:       base64.RawStdEncoding.EncodeToString(uncompressed),
+			attestPath:   attestationPath(cfg, h),
+			requiresAuth: keyRequiresAuth(pub),
+			reuseSession: cfg.ReuseSession,
 		}, nil
 	}
 
@@ -186,7 +232,7 @@ func openOrCreateAtHandle(rwc io.ReadWriteCloser, cfg Config, h tpmutil.Handle)
 }
 
 func createAndPersistAt(rwc io.ReadWriteCloser, cfg Config, handle tpmutil.Handle) (Client, error) {
-	transient, uncompressed, err := createPrimarySigningKey(rwc)
+	transient, uncompressed, att, err := createPrimarySigningKey(rwc, cfg.KeyAuth, cfg.EndorsementAuth)
 	if err != nil {
 		return nil, err
 	}
@@ -199,13 +245,115 @@ func createAndPersistAt(rwc io.ReadWriteCloser, cfg Config, handle tpmutil.Handl
 	_ = tpm2.FlushContext(rwc, transient)
 
 	log.Info("tpmdevice persisted ECC key", "handle", fmt.Sprintf("0x%x", handle))
+	return persistedClient(rwc, cfg, handle, uncompressed, att), nil
+}
+
+// replaceAt is ForceNew for handle h, ordered so an interruption never leaves the device
+// without a key: the new key is created and persisted at a free staging handle first,
+// then the old key is evicted and the new one persisted at h, then the staging copy is
+// dropped. If the final step fails, the client uses the staging handle instead. Without
+// a free staging handle an occupied h is left alone and ErrNoStagingHandle is returned.
+func replaceAt(rwc io.ReadWriteCloser, cfg Config, h tpmutil.Handle) (Client, error) {
+	staging, ok := findFreeHandle(rwc, cfg, h)
+	if !ok {
+		_, _, _, err := tpm2.ReadPublic(rwc, h)
+		if isHandleEmptyErr(err) {
+			return createAndPersistAt(rwc, cfg, h)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: replacing key at 0x%x", ErrNoStagingHandle, h)
+	}
+
+	transient, uncompressed, att, err := createPrimarySigningKey(rwc, cfg.KeyAuth, cfg.EndorsementAuth)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tpm2.FlushContext(rwc, transient) }()
+
+	if err := tpm2.EvictControl(rwc, cfg.OwnerAuth, tpm2.HandleOwner, transient, staging); err != nil {
+		return nil, fmt.Errorf("tpmdevice: persist new key at staging handle 0x%x: %w", staging, err)
+	}
+
+	if _, _, _, err := tpm2.ReadPublic(rwc, h); err == nil {
+		if err := tpm2.EvictControl(rwc, cfg.OwnerAuth, tpm2.HandleOwner, h, h); err != nil {
+			_ = tpm2.EvictControl(rwc, cfg.OwnerAuth, tpm2.HandleOwner, staging, staging)
+			return nil, fmt.Errorf("tpmdevice: evict old key 0x%x: %w", h, err)
+		}
+	} else if !isHandleEmptyErr(err) {
+		_ = tpm2.EvictControl(rwc, cfg.OwnerAuth, tpm2.HandleOwner, staging, staging)
+		return nil, err
+	}
+
+	if err := tpm2.EvictControl(rwc, cfg.OwnerAuth, tpm2.HandleOwner, transient, h); err != nil {
+		log.Error("tpmdevice failed to move new key into place; keeping it at the staging handle",
+			"handle", fmt.Sprintf("0x%x", h),
+			"staging", fmt.Sprintf("0x%x", staging),
+			"error", err,
+		)
+		return persistedClient(rwc, cfg, staging, uncompressed, att), nil
+	}
+	if err := tpm2.EvictControl(rwc, cfg.OwnerAuth, tpm2.HandleOwner, staging, staging); err != nil {
+		log.Warn("tpmdevice failed to drop staging copy of new key",
+			"staging", fmt.Sprintf("0x%x", staging),
+			"error", err,
+		)
+	}
+
+	log.Info("tpmdevice replaced ECC key", "handle", fmt.Sprintf("0x%x", h))
+	return persistedClient(rwc, cfg, h, uncompressed, att), nil
+}
+
+// findFreeHandle returns an empty handle in the configured range other than exclude.
+func findFreeHandle(rwc io.ReadWriter, cfg Config, exclude tpmutil.Handle) (tpmutil.Handle, bool) {
+	start := cfg.HandleStart
+	if start == 0 {
+		start = defaultHandleStart
+	}
+	count := cfg.HandleCount
+	if count == 0 {
+		count = defaultHandleCount
+	}
+
+	for i := uint32(0); i < count; i++ {
+		h := tpmutil.Handle(uint32(start) + i)
+		if h == exclude {
+			continue
+		}
+		if _, _, _, err := tpm2.ReadPublic(rwc, h); isHandleEmptyErr(err) {
+			return h, true
+		}
+	}
+	return 0, false
+}
+
+// persistedClient wraps a freshly persisted key and stores its creation attestation.
+func persistedClient(rwc io.ReadWriteCloser, cfg Config, handle tpmutil.Handle, uncompressed []byte, att *creationAttestationV2) *client {
+	attestPath := attestationPath(cfg, handle)
+	// Drop any attestation left over from a key previously at this handle.
+	if attestPath != "" {
+		_ = os.Remove(attestPath)
+	}
+	if att != nil {
+		att.Handle = uint32(handle)
+		if err := writeCreationAttestation(attestPath, att); err != nil {
+			log.Warn("tpmdevice failed to store creation attestation",
+				"handle", fmt.Sprintf("0x%x", handle),
+				"error", err,
+			)
+		}
+	}
 
 	return &client{
-		rwc:    rwc,
-		handle: handle,
-		pub:    uncompressed,
-		pubB64: base64.RawStdEncoding.EncodeToString(uncompressed),
-	}, nil
+		rwc:          rwc,
+		handle:       handle,
+		pub:          uncompressed,
+		pubB64:       base64.RawStdEncoding.EncodeToString(uncompressed),
+		attestPath:   attestPath,
+		requiresAuth: cfg.KeyAuth != "",
+		reuseSession: cfg.ReuseSession,
+	}
 }
 
 func isHandleEmptyErr(err error) bool {
@@ -223,8 +371,9 @@ func isHandleEmptyErr(err error) bool {
 // createPrimarySigningKey creates a transient ECC signing key and returns
 // its handle + uncompressed public key. No retry logic – any hierarchy/driver
 // issue is surfaced directly to the caller.
-// createPrimarySigningKey creates a transient ECC signing key.
-func createPrimarySigningKey(rwc io.ReadWriter) (tpmutil.Handle, []byte, error) {
+// The creation attestation is best-effort: nil if the TPM refused to certify.
+// A non-empty keyAuth creates a PolicyPassword-gated key (see Config.KeyAuth).
+func createPrimarySigningKey(rwc io.ReadWriter, keyAuth, endorsementAuth string) (tpmutil.Handle, []byte, *creationAttestationV2, error) {
 	template := tpm2.Public{
 		Type:    tpm2.AlgECC,
 		NameAlg: tpm2.AlgSHA256,
@@ -237,33 +386,44 @@ func createPrimarySigningKey(rwc io.ReadWriter) (tpmutil.Handle, []byte, error)
 			CurveID: tpm2.CurveNISTP256,
 		},
 	}
+	if keyAuth != "" {
+		// Policy-only: the auth value can't be used in a plain password session.
+		template.Attributes &^= tpm2.FlagUserWithAuth
+		template.AuthPolicy = policyPasswordDigest()
+	}
 
-	handle, _, err := tpm2.CreatePrimary(
+	handle, _, _, creationHash, ticket, _, err := tpm2.CreatePrimaryEx(
 		rwc,
 		tpm2.HandleOwner,
 		tpm2.PCRSelection{},
 		"",
-		"",
+		keyAuth,
 		template,
 	)
 	if err != nil {
 		log.Error("tpmdevice CreatePrimary failed", "error", err)
-		return 0, nil, err
+		return 0, nil, nil, err
 	}
 
-	pub, _, _, err := tpm2.ReadPublic(rwc, handle)
+	pub, name, _, err := tpm2.ReadPublic(rwc, handle)
 	if err != nil {
 		_ = tpm2.FlushContext(rwc, handle)
-		return 0, nil, err
+		return 0, nil, nil, err
 	}
 
 	uncompressed, err := publicToUncompressed(pub)
 	if err != nil {
 		_ = tpm2.FlushContext(rwc, handle)
-		return 0, nil, err
+		return 0, nil, nil, err
+	}
+
+	att, err := certifyCreation(rwc, endorsementAuth, handle, pub, name, creationHash, ticket)
+	if err != nil {
+		log.Warn("tpmdevice creation attestation unavailable", "error", err)
+		att = nil
 	}
 
-	return handle, uncompressed, nil
+	return handle, uncompressed, att, nil
 }
 
 func publicToUncompressed(pub tpm2.Public) ([]byte, error) {
@@ -292,6 +452,9 @@ func (c *client) Sign(msg []byte) ([]byte, error) {
 	if c == nil || c.rwc == nil {
 		return nil, fmt.Errorf("tpmdevice: client not initialized")
 	}
+	if c.requiresAuth {
+		return nil, ErrKeyAuthRequired
+	}
 	d := sha256.Sum256(msg)
 	sig, err := tpm2.Sign(
 		c.rwc,
@@ -322,11 +485,97 @@ func (c *client) SignB64(msg []byte) (string, error) {
 	return base64.RawStdEncoding.EncodeToString(raw), nil
 }
 
+func (c *client) RequiresAuth() bool {
+	return c != nil && c.requiresAuth
+}
+
+// SignWithAuth signs with a key created with Config.KeyAuth, satisfying its
+// PolicyPassword with auth. For a key without auth it is the same as Sign.
+func (c *client) SignWithAuth(msg []byte, auth string) ([]byte, error) {
+	if c == nil || c.rwc == nil {
+		return nil, fmt.Errorf("tpmdevice: client not initialized")
+	}
+	if !c.requiresAuth {
+		return c.Sign(msg)
+	}
+
+	d := sha256.Sum256(msg)
+	if c.reuseSession {
+		return c.signWithReusedSession(d[:], auth)
+	}
+
+	sess, err := startPolicySession(c.rwc)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tpm2.FlushContext(c.rwc, sess) }()
+	return c.signInPolicySession(sess, d[:], auth)
+}
+
+// signInPolicySession satisfies the key's PolicyPassword in sess and signs digest.
+func (c *client) signInPolicySession(sess tpmutil.Handle, digest []byte, auth string) ([]byte, error) {
+	if err := tpm2.PolicyPassword(c.rwc, sess); err != nil {
+		return nil, fmt.Errorf("tpmdevice: PolicyPassword: %w", err)
+	}
+
+	sig, err := tpm2.SignWithSession(
+		c.rwc,
+		sess,
+		c.handle,
+		auth,
+		digest,
+		nil,
+		&tpm2.SigScheme{
+			Alg:  tpm2.AlgECDSA,
+			Hash: tpm2.AlgSHA256,
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("tpmdevice: Sign: %w", err)
+	}
+	if sig.ECC == nil {
+		return nil, fmt.Errorf("tpmdevice: TPM returned non-ECC signature")
+	}
+	return append(pad32(sig.ECC.R), pad32(sig.ECC.S)...), nil
+}
+
+func (c *client) SignWithAuthB64(msg []byte, auth string) (string, error) {
+	raw, err := c.SignWithAuth(msg, auth)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawStdEncoding.EncodeToString(raw), nil
+}
+
+// tpmCCPolicyPassword is TPM_CC_PolicyPassword.
+const tpmCCPolicyPassword = 0x0000018C
+
+// policyPasswordDigest is the policy digest after a single TPM2_PolicyPassword from
+// a fresh SHA-256 session: SHA256(zeros || TPM_CC_PolicyPassword).
+func policyPasswordDigest() []byte {
+	var buf [sha256.Size + 4]byte
+	binary.BigEndian.PutUint32(buf[sha256.Size:], tpmCCPolicyPassword)
+	d := sha256.Sum256(buf[:])
+	return d[:]
+}
+
+// keyRequiresAuth reports whether a persisted key can only be used through a policy.
+func keyRequiresAuth(pub tpm2.Public) bool {
+	return len(pub.AuthPolicy) > 0 && pub.Attributes&tpm2.FlagUserWithAuth == 0
+}
+
 func (c *client) Close() error {
 	if c == nil || c.rwc == nil {
 		return nil
 	}
 
+	c.sessMu.Lock()
+	if c.sess != 0 {
+		_ = tpm2.FlushContext(c.rwc, c.sess)
+		c.sess = 0
+	}
+	c.sessMu.Unlock()
+
 	err := c.rwc.Close()
 	c.rwc = nil // make Close idempotent
 
@@ -344,3 +593,72 @@ func (c *client) Close() error {
 
 	return fmt.Errorf("tpmdevice: close: %w", err)
 }
+
+// Deprovision evicts the persistent signing key and confirms the handle is empty.
+// With cfg.Handle unset it removes the key NewWithConfig would have picked: the first
+// compatible ECC key in [HandleStart, HandleStart+HandleCount). Any stored creation
+// attestation for the handle is deleted too. Unlike ForceNew, no replacement key is created.
+func Deprovision(ctx context.Context, cfg Config) error {
+	if runtime.GOOS == "darwin" {
+		return fmt.Errorf("tpmdevice: Deprovision is not supported by the Secure Enclave backend")
+	}
+
+	rwc, err := openTPM()
+	if err != nil {
+		return err
+	}
+	defer rwc.Close()
+
+	h := cfg.Handle
+	if h == 0 {
+		h, err = findCompatibleHandle(rwc, cfg)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := tpm2.EvictControl(rwc, cfg.OwnerAuth, tpm2.HandleOwner, h, h); err != nil {
+		return fmt.Errorf("tpmdevice: evict 0x%x: %w", h, err)
+	}
+
+	if _, _, _, err := tpm2.ReadPublic(rwc, h); err == nil {
+		return fmt.Errorf("tpmdevice: key still present at 0x%x after evict", h)
+	} else if !isHandleEmptyErr(err) {
+		return fmt.Errorf("tpmdevice: verify evict 0x%x: %w", h, err)
+	}
+
+	if p := attestationPath(cfg, h); p != "" {
+		_ = os.Remove(p)
+	}
+
+	log.Info("tpmdevice deprovisioned key", "handle", fmt.Sprintf("0x%x", h))
+	return nil
+}
+
+// findCompatibleHandle returns the first handle in the configured range holding an ECC key.
+func findCompatibleHandle(rwc io.ReadWriter, cfg Config) (tpmutil.Handle, error) {
+	start := cfg.HandleStart
+	if start == 0 {
+		start = defaultHandleStart
+	}
+	count := cfg.HandleCount
+	if count == 0 {
+		count = defaultHandleCount
+	}
+
+	for i := uint32(0); i < count; i++ {
+		h := tpmutil.Handle(uint32(start) + i)
+		pub, _, _, err := tpm2.ReadPublic(rwc, h)
+		if err != nil {
+			if isHandleEmptyErr(err) {
+				continue
+			}
+			return 0, err
+		}
+		if _, err := publicToUncompressed(pub); err == nil {
+			return h, nil
+		}
+	}
+	return 0, fmt.Errorf("tpmdevice: no key found in range 0x%x..0x%x",
+		start, tpmutil.Handle(uint32(start)+count-1))
+}